@@ -0,0 +1,264 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const baselineVersion = 1
+
+// DefaultBaselineFile is used by `lint --write-baseline` when lint.baseline is not configured.
+const DefaultBaselineFile = "mxlint-baseline.json"
+
+type baselineFile struct {
+	Version    int             `json:"version"`
+	Violations []baselineEntry `json:"violations"`
+}
+
+// baselineEntry identifies accepted violations. Count is the number of
+// identical violations accepted in the document and is omitted when it is 1.
+// The message is kept for reviewers of the committed file; matching only uses
+// the fingerprint.
+type baselineEntry struct {
+	Rule        string `json:"rule"`
+	Document    string `json:"document"`
+	Fingerprint string `json:"fingerprint"`
+	Count       int    `json:"count,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+type baselineKey struct {
+	rule        string
+	document    string
+	fingerprint string
+}
+
+// lintBaseline is the in-memory lookup of a baseline file, with the number
+// of accepted violations per key. A nil baseline accepts nothing, so callers
+// do not need to check whether one is configured.
+type lintBaseline struct {
+	entries map[baselineKey]int
+}
+
+func baselineRuleID(rule Rule) string {
+	if rule.RuleNumber != "" {
+		return rule.RuleNumber
+	}
+	return filepath.ToSlash(rule.Path)
+}
+
+// fingerprintMessage hashes a single violation message. Surrounding
+// whitespace is ignored so re-indented messages keep their fingerprint.
+func fingerprintMessage(message string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(message)))
+	return fmt.Sprintf("%x", sum[:])
+}
+
+// failureViolations returns the violations of a failed testcase, or its
+// failure message as a single violation when the rule reported none.
+func failureViolations(tc Testcase) []Violation {
+	if tc.Failure == nil {
+		return nil
+	}
+	if len(tc.Violations) > 0 {
+		return tc.Violations
+	}
+	return []Violation{{Message: tc.Failure.Message}}
+}
+
+func violationMessages(violations []Violation) string {
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.Message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// apply moves violations recorded in the baseline from Failure to Baselined.
+// A key accepts at most as many identical violations as were recorded, and
+// testcases keep failing when they still have violations beyond the baseline.
+func (b *lintBaseline) apply(testsuite *Testsuite, rule Rule) {
+	if b == nil || testsuite == nil {
+		return
+	}
+	ruleID := baselineRuleID(rule)
+	accepted := map[baselineKey]int{}
+	for i := range testsuite.Testcases {
+		tc := &testsuite.Testcases[i]
+		if tc.Failure == nil {
+			continue
+		}
+
+		known := make([]Violation, 0)
+		unknown := make([]Violation, 0)
+		for _, v := range failureViolations(*tc) {
+			key := baselineKey{rule: ruleID, document: tc.Name, fingerprint: fingerprintMessage(v.Message)}
+			if accepted[key] < b.entries[key] {
+				accepted[key]++
+				known = append(known, v)
+			} else {
+				unknown = append(unknown, v)
+			}
+		}
+		if len(known) == 0 {
+			continue
+		}
+
+		tc.Baselined = &Baselined{Message: violationMessages(known)}
+		testsuite.Baselined++
+		if len(unknown) == 0 {
+			tc.Violations = nil
+			tc.Failure = nil
+			testsuite.Failures--
+			continue
+		}
+		if len(tc.Violations) > 0 {
+			tc.Violations = unknown
+		}
+		tc.Failure = &Failure{
+			Message: violationMessages(unknown),
+			Type:    tc.Failure.Type,
+			Data:    tc.Failure.Data,
+		}
+	}
+}
+
+func loadBaseline(path string) (*lintBaseline, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file baselineFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if file.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s", file.Version, path)
+	}
+
+	baseline := &lintBaseline{entries: make(map[baselineKey]int, len(file.Violations))}
+	for _, entry := range file.Violations {
+		count := entry.Count
+		if count < 1 {
+			count = 1
+		}
+		baseline.entries[baselineKey{
+			rule:        entry.Rule,
+			document:    entry.Document,
+			fingerprint: entry.Fingerprint,
+		}] += count
+	}
+	return baseline, nil
+}
+
+// loadConfiguredBaseline loads lint.baseline when configured. A configured
+// baseline that does not exist yet is treated as empty.
+func loadConfiguredBaseline() (*lintBaseline, error) {
+	path := baselineFilePath()
+	if path == "" {
+		return nil, nil
+	}
+	baseline, err := loadBaseline(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("Baseline file %s not found; all violations count as failures", path)
+			return nil, nil
+		}
+		return nil, err
+	}
+	log.Debugf("Loaded %d baselined violations from %s", len(baseline.entries), path)
+	return baseline, nil
+}
+
+func buildBaselineFile(testsuites []Testsuite, rules []Rule) baselineFile {
+	indexes := map[baselineKey]int{}
+	entries := make([]baselineEntry, 0)
+	for i, ts := range testsuites {
+		ruleID := baselineRuleID(rules[i])
+		for _, tc := range ts.Testcases {
+			for _, v := range failureViolations(tc) {
+				key := baselineKey{rule: ruleID, document: tc.Name, fingerprint: fingerprintMessage(v.Message)}
+				if index, ok := indexes[key]; ok {
+					entries[index].Count++
+					continue
+				}
+				indexes[key] = len(entries)
+				entries = append(entries, baselineEntry{
+					Rule:        key.rule,
+					Document:    key.document,
+					Fingerprint: key.fingerprint,
+					Count:       1,
+					Message:     strings.TrimSpace(v.Message),
+				})
+			}
+		}
+	}
+	for i := range entries {
+		if entries[i].Count == 1 {
+			entries[i].Count = 0
+		}
+	}
+
+	// Sort so the committed file produces small, reviewable diffs.
+	slices.SortFunc(entries, func(a, b baselineEntry) int {
+		if c := strings.Compare(a.Rule, b.Rule); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Document, b.Document); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Fingerprint, b.Fingerprint)
+	})
+
+	return baselineFile{Version: baselineVersion, Violations: entries}
+}
+
+func writeBaselineFile(path string, file baselineFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// WriteBaseline evaluates all rules and records every current violation in
+// baselinePath. Any existing baseline is ignored so the file is rebuilt from scratch.
+// It returns the number of recorded violations.
+func WriteBaseline(rulesPath string, modelSourcePath string, baselinePath string, ignoreNoqa bool, useCache bool) (int, error) {
+	rules, err := prepareRules(rulesPath)
+	if err != nil {
+		return 0, err
+	}
+
+	testsuites, err := evalRules(rules, modelSourcePath, ignoreNoqa, useCache, nil, resultPolicy{})
+	if err != nil {
+		return 0, err
+	}
+
+	file := buildBaselineFile(testsuites, rules)
+	if err := writeBaselineFile(baselinePath, file); err != nil {
+		return 0, fmt.Errorf("failed to write baseline %s: %w", baselinePath, err)
+	}
+	return len(file.Violations), nil
+}
+
+func logBaselineSummary(testsuites []Testsuite) {
+	baselinedCount := 0
+	for _, ts := range testsuites {
+		baselinedCount += ts.Baselined
+	}
+	if baselinedCount > 0 {
+		log.Infof("Baselined documents (not counted as failures): %d", baselinedCount)
+	}
+}
@@ -0,0 +1,271 @@
+package lint
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAlwaysFailRule(t *testing.T, dir string, ruleNumber string, errors string) string {
+	t.Helper()
+	return writeFailingRule(t, dir, ruleNumber, "HIGH", errors)
+}
+
+func writeAlwaysFailRuleWithSeverity(t *testing.T, dir string, ruleNumber string, severity string) string {
+	t.Helper()
+	return writeFailingRule(t, dir, ruleNumber, severity, `["always fails"]`)
+}
+
+func writeTestFile(dir string, name string, content string) error {
+	return os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+}
+
+func writeFailingRule(t *testing.T, dir string, ruleNumber string, severity string, errors string) string {
+	t.Helper()
+	jsContent := `
+const metadata = {
+    title: "Always Fail Rule",
+    description: "This rule always fails",
+    custom: {
+        category: "Test",
+        rulename: "AlwaysFailRule",
+        severity: "` + severity + `",
+        rulenumber: "` + ruleNumber + `",
+        remediation: "Cannot be fixed",
+        input: ".*\\.yaml"
+    }
+};
+
+function rule(input) {
+    return { allow: false, errors: ` + errors + ` };
+}
+`
+	rulePath := filepath.Join(dir, ruleNumber+"_always_fail.js")
+	if err := os.WriteFile(rulePath, []byte(jsContent), 0644); err != nil {
+		t.Fatalf("Failed to write js file: %v", err)
+	}
+	return rulePath
+}
+
+func TestBaselineApply(t *testing.T) {
+	rule := Rule{Path: "rules/fail.js", RuleNumber: "099_0001"}
+	baseline := &lintBaseline{entries: map[baselineKey]int{
+		{rule: "099_0001", document: "A.yaml", fingerprint: fingerprintMessage("known")}: 1,
+		{rule: "099_0001", document: "B.yaml", fingerprint: fingerprintMessage("known")}: 1,
+		{rule: "099_0002", document: "C.yaml", fingerprint: fingerprintMessage("other")}: 1,
+	}}
+
+	testsuite := &Testsuite{
+		Name:     rule.Path,
+		Failures: 3,
+		Testcases: []Testcase{
+			{Name: "A.yaml", Failure: &Failure{Message: "known", Type: "AssertionError"}},
+			{Name: "B.yaml", Failure: &Failure{Message: "known\nnew", Type: "AssertionError"}, Violations: []Violation{{Message: "known"}, {Message: "new", Path: "/Name"}}},
+			{Name: "C.yaml", Failure: &Failure{Message: "other", Type: "AssertionError"}},
+			{Name: "D.yaml"},
+		},
+	}
+
+	baseline.apply(testsuite, rule)
+
+	if testsuite.Failures != 2 {
+		t.Fatalf("expected 2 remaining failures, got %d", testsuite.Failures)
+	}
+	if testsuite.Baselined != 2 {
+		t.Fatalf("expected 2 baselined testcases, got %d", testsuite.Baselined)
+	}
+	if tc := testsuite.Testcases[0]; tc.Failure != nil || tc.Baselined == nil || tc.Baselined.Message != "known" {
+		t.Fatalf("expected fully baselined testcase, got %+v", tc)
+	}
+	if tc := testsuite.Testcases[1]; tc.Failure == nil || tc.Failure.Message != "new" || tc.Baselined == nil {
+		t.Fatalf("expected only the new violation to fail, got %+v", tc)
+	}
+	if tc := testsuite.Testcases[1]; len(tc.Violations) != 1 || tc.Violations[0].Path != "/Name" {
+		t.Fatalf("expected baselined violations to be dropped, got %+v", tc.Violations)
+	}
+	if tc := testsuite.Testcases[2]; tc.Failure == nil || tc.Baselined != nil {
+		t.Fatalf("expected baseline entries of other rules to be ignored, got %+v", tc)
+	}
+}
+
+func TestBaselineApply_CountsIdenticalViolations(t *testing.T) {
+	rule := Rule{Path: "rules/fail.js", RuleNumber: "099_0001"}
+	multiline := "first line\nsecond line"
+	violations := []Violation{{Message: "duplicate"}, {Message: multiline}}
+	recorded := buildBaselineFile([]Testsuite{{Testcases: []Testcase{
+		{Name: "A.yaml", Failure: newAssertionFailure(violations), Violations: violations},
+	}}}, []Rule{rule})
+	if len(recorded.Violations) != 2 || recorded.Violations[0].Count != 0 || recorded.Violations[1].Count != 0 {
+		t.Fatalf("expected one entry per violation, got %+v", recorded.Violations)
+	}
+
+	baseline := &lintBaseline{entries: map[baselineKey]int{}}
+	for _, entry := range recorded.Violations {
+		baseline.entries[baselineKey{rule: entry.Rule, document: entry.Document, fingerprint: entry.Fingerprint}] = 1
+	}
+	current := []Violation{{Message: "duplicate", Line: 3}, {Message: multiline}, {Message: "duplicate", Line: 7}}
+	testsuite := &Testsuite{
+		Failures:  1,
+		Testcases: []Testcase{{Name: "A.yaml", Failure: newAssertionFailure(current), Violations: current}},
+	}
+
+	baseline.apply(testsuite, rule)
+
+	tc := testsuite.Testcases[0]
+	if testsuite.Failures != 1 || tc.Failure == nil || tc.Failure.Message != "duplicate" {
+		t.Fatalf("expected the second identical violation to fail, got %+v", tc)
+	}
+	if len(tc.Violations) != 1 || tc.Violations[0].Line != 7 {
+		t.Fatalf("expected only the violation beyond the baseline to remain, got %+v", tc.Violations)
+	}
+	if tc.Baselined == nil || tc.Baselined.Message != "duplicate\n"+multiline {
+		t.Fatalf("expected the recorded violations to be baselined, got %+v", tc.Baselined)
+	}
+
+	recorded = buildBaselineFile([]Testsuite{{Testcases: []Testcase{
+		{Name: "A.yaml", Failure: newAssertionFailure(current), Violations: current},
+	}}}, []Rule{rule})
+	for _, entry := range recorded.Violations {
+		if entry.Message == "duplicate" && entry.Count != 2 {
+			t.Fatalf("expected identical violations to be counted, got %+v", recorded.Violations)
+		}
+	}
+}
+
+func TestBaselineApplyNil(t *testing.T) {
+	var baseline *lintBaseline
+	testsuite := &Testsuite{Failures: 1, Testcases: []Testcase{{Name: "A.yaml", Failure: &Failure{Message: "x"}}}}
+	baseline.apply(testsuite, Rule{RuleNumber: "099_0001"})
+	if testsuite.Failures != 1 || testsuite.Testcases[0].Failure == nil {
+		t.Fatal("expected nil baseline to leave failures untouched")
+	}
+}
+
+func TestWriteBaselineAndEvalAll(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	writeAlwaysFailRule(t, rulesDir, "099_0001", `["legacy violation"]`)
+	if err := os.WriteFile(filepath.Join(modelDir, "Doc.yaml"), []byte(`Name: "Test"`), 0644); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	baselinePath := filepath.Join(t.TempDir(), "nested", "baseline.json")
+	SetConfig(&Config{})
+	count, err := WriteBaseline(rulesDir, modelDir, baselinePath, false, false)
+	if err != nil {
+		t.Fatalf("WriteBaseline returned error: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 recorded violation, got %d", count)
+	}
+
+	content, err := os.ReadFile(baselinePath)
+	if err != nil {
+		t.Fatalf("expected baseline file: %v", err)
+	}
+	if !strings.Contains(string(content), `"document": "Doc.yaml"`) || !strings.Contains(string(content), `"rule": "099_0001"`) {
+		t.Fatalf("unexpected baseline content: %s", content)
+	}
+
+	t.Run("baselined violations do not fail", func(t *testing.T) {
+		SetConfig(&Config{Lint: ConfigLintSpec{Baseline: baselinePath}})
+		xunitPath := filepath.Join(t.TempDir(), "report.xml")
+
+		if err := EvalAll(rulesDir, modelDir, xunitPath, "", "", false, false, nil); err != nil {
+			t.Fatalf("expected baselined violations to pass, got: %v", err)
+		}
+
+		report, err := os.ReadFile(xunitPath)
+		if err != nil {
+			t.Fatalf("expected xunit report: %v", err)
+		}
+		var suites TestSuites
+		if err := xml.Unmarshal(report, &suites); err != nil {
+			t.Fatalf("failed to parse xunit report: %v", err)
+		}
+		if suites.Testsuites[0].Baselined != 1 || suites.Testsuites[0].Testcases[0].Baselined == nil {
+			t.Fatalf("expected baselined testcase in xunit report, got %+v", suites.Testsuites[0])
+		}
+	})
+
+	t.Run("new violations still fail", func(t *testing.T) {
+		SetConfig(&Config{Lint: ConfigLintSpec{Baseline: baselinePath}})
+		writeAlwaysFailRule(t, rulesDir, "099_0001", `["legacy violation", "new violation"]`)
+
+		err := EvalAll(rulesDir, modelDir, "", "", "", false, false, nil)
+		if err == nil || err.Error() != "1 failures" {
+			t.Fatalf("expected the new violation to fail, got: %v", err)
+		}
+	})
+}
+
+func TestWriteBaseline_RulesData(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+		setRulesData(nil, "")
+	})
+	SetConfig(&Config{})
+	setRulesData(nil, "")
+
+	rulesDir := t.TempDir()
+	writeRulesDataFile(t, filepath.Join(rulesDir, rulesDataDirectory), "approved_modules.yaml", "- Administration\n")
+	rule := `# METADATA
+# title: Approved modules
+# description: Modules must be approved
+# custom:
+#   rulenumber: "001_0001"
+#   input: ".*Module.yaml"
+package test.approved
+
+import rego.v1
+
+default allow := false
+
+allow if input.Name in data.mxlint.external.approved_modules
+
+errors := [sprintf("Module %s is not approved", [input.Name])] if not allow else := []
+`
+	if err := writeTestFile(rulesDir, "001_0001_approved.rego", rule); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	modelDir := t.TempDir()
+	for _, name := range []string{"Administration", "Unknown"} {
+		if err := writeTestFile(modelDir, name+"Module.yaml", "Name: "+name+"\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	baselinePath := filepath.Join(t.TempDir(), "baseline.json")
+	count, err := WriteBaseline(rulesDir, modelDir, baselinePath, false, false)
+	if err != nil {
+		t.Fatalf("WriteBaseline returned error: %v", err)
+	}
+	content, err := os.ReadFile(baselinePath)
+	if err != nil {
+		t.Fatalf("expected baseline file: %v", err)
+	}
+	if count != 1 || !strings.Contains(string(content), `"document": "UnknownModule.yaml"`) {
+		t.Fatalf("expected only the unapproved module to be recorded, got %d: %s", count, content)
+	}
+}
+
+func TestLoadConfiguredBaseline_MissingFileIsEmpty(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{Lint: ConfigLintSpec{Baseline: filepath.Join(t.TempDir(), "missing.json")}})
+
+	baseline, err := loadConfiguredBaseline()
+	if err != nil {
+		t.Fatalf("expected missing baseline to be ignored, got: %v", err)
+	}
+	if baseline != nil {
+		t.Fatal("expected nil baseline for missing file")
+	}
+}
@@ -12,7 +12,7 @@ import (
 	"sync"
 )
 
-const cacheVersion = "v2"
+const cacheVersion = "v3"
 
 var cacheDirConfig = struct {
 	mu  sync.RWMutex
@@ -88,36 +88,19 @@ func computeFileHash(filePath string) (string, error) {
 	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
 }
 
-// createCacheKey creates a cache key from rule and input file paths
-func createCacheKey(rulePath string, inputFilePath string) (*CacheKey, error) {
-	ruleHash, err := computeFileHash(rulePath)
-	if err != nil {
-		return nil, err
-	}
-
-	inputHash, err := computeFileHash(inputFilePath)
-	if err != nil {
-		return nil, err
-	}
-
-	configHash := computeCacheConfigHash()
-
-	return &CacheKey{
-		RuleHash:   ruleHash,
-		InputHash:  inputHash,
-		ConfigHash: configHash,
-	}, nil
-}
-
 // computeCacheConfigHash returns a stable hash of config fields that can
 // influence lint outcomes while still allowing cache reuse when irrelevant
 // settings (e.g. verbosity) change.
 func computeCacheConfigHash() string {
 	cfg := getConfig()
-	if cfg == nil || len(cfg.Lint.Skip) == 0 {
+	dataHash := rulesDataHash()
+	if dataHash == "" && (cfg == nil || (len(cfg.Lint.Skip) == 0 && len(cfg.Rules.Settings) == 0)) {
 		sum := sha256.Sum256([]byte("skip:{}"))
 		return fmt.Sprintf("%x", sum[:])
 	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
 
 	normalizedSkip := map[string][]ConfigSkipRule{}
 	keys := make([]string, 0, len(cfg.Lint.Skip))
@@ -148,11 +131,31 @@ func computeCacheConfigHash() string {
 			builder.WriteString(entry.Reason)
 			builder.WriteString("|")
 			builder.WriteString(entry.Date)
+			builder.WriteString("|")
+			builder.WriteString(entry.Expires)
+			// Cached skips must not outlive the entry.
+			if skipEntryExpired(entry) {
+				builder.WriteString("|expired")
+			}
 		}
 		builder.WriteString("];")
 	}
 	builder.WriteString("}")
 
+	if len(cfg.Rules.Settings) > 0 {
+		// encoding/json sorts map keys, which keeps the hash stable.
+		settings, err := json.Marshal(cfg.Rules.Settings)
+		if err != nil {
+			log.Debugf("Error hashing rule settings: %v", err)
+		}
+		builder.WriteString("settings:")
+		builder.Write(settings)
+	}
+	if dataHash != "" {
+		builder.WriteString("data:")
+		builder.WriteString(dataHash)
+	}
+
 	sum := sha256.Sum256([]byte(builder.String()))
 	return fmt.Sprintf("%x", sum[:])
 }
@@ -7,6 +7,27 @@ import (
 	"testing"
 )
 
+// createCacheKey creates a cache key from rule and input file paths
+func createCacheKey(rulePath string, inputFilePath string) (*CacheKey, error) {
+	ruleHash, err := computeRuleHash(rulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	inputHash, err := computeFileHash(inputFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	configHash := computeCacheConfigHash()
+
+	return &CacheKey{
+		RuleHash:   ruleHash,
+		InputHash:  inputHash,
+		ConfigHash: configHash,
+	}, nil
+}
+
 func TestCaching(t *testing.T) {
 	// Create a temporary cache directory for testing
 	tempDir := t.TempDir()
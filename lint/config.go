@@ -27,15 +27,34 @@ type Config struct {
 }
 
 type ConfigRulesSpec struct {
-	Path     string   `yaml:"path"`
-	Rulesets []string `yaml:"rulesets"`
-	rulesetsSet bool
+	Path        string                            `yaml:"path"`
+	Rulesets    []string                          `yaml:"rulesets"`
+	Data        []string                          `yaml:"data"`
+	Settings    map[string]map[string]interface{} `yaml:"settings"`
+	Include     []ConfigRuleSelector              `yaml:"include"`
+	Exclude     []ConfigRuleSelector              `yaml:"exclude"`
+	MinSeverity string                            `yaml:"minSeverity"`
+	Overrides   map[string]ConfigRuleOverride     `yaml:"overrides"`
+	// CoverageThreshold is the lowest coverage, in percent, of a Rego rule by
+	// its test cases that test-rules accepts.
+	CoverageThreshold *float64 `yaml:"coverageThreshold"`
+	rulesetsSet       bool
+	dataSet           bool
+	includeSet        bool
+	excludeSet        bool
 }
 
 func (c *ConfigRulesSpec) UnmarshalYAML(value *yaml.Node) error {
 	type configRulesSpecAlias struct {
-		Path     string   `yaml:"path"`
-		Rulesets []string `yaml:"rulesets"`
+		Path              string                            `yaml:"path"`
+		Rulesets          []string                          `yaml:"rulesets"`
+		Data              []string                          `yaml:"data"`
+		Settings          map[string]map[string]interface{} `yaml:"settings"`
+		Include           []ConfigRuleSelector              `yaml:"include"`
+		Exclude           []ConfigRuleSelector              `yaml:"exclude"`
+		MinSeverity       string                            `yaml:"minSeverity"`
+		Overrides         map[string]ConfigRuleOverride     `yaml:"overrides"`
+		CoverageThreshold *float64                          `yaml:"coverageThreshold"`
 	}
 
 	var decoded configRulesSpecAlias
@@ -45,13 +64,29 @@ func (c *ConfigRulesSpec) UnmarshalYAML(value *yaml.Node) error {
 
 	c.Path = decoded.Path
 	c.Rulesets = append([]string{}, decoded.Rulesets...)
+	c.Data = append([]string{}, decoded.Data...)
+	c.Settings = decoded.Settings
+	c.Include = append([]ConfigRuleSelector{}, decoded.Include...)
+	c.Exclude = append([]ConfigRuleSelector{}, decoded.Exclude...)
+	c.MinSeverity = decoded.MinSeverity
+	c.Overrides = decoded.Overrides
+	c.CoverageThreshold = decoded.CoverageThreshold
 	c.rulesetsSet = false
+	c.dataSet = false
+	c.includeSet = false
+	c.excludeSet = false
 
 	if value.Kind == yaml.MappingNode {
 		for i := 0; i+1 < len(value.Content); i += 2 {
-			if value.Content[i].Value == "rulesets" {
+			switch value.Content[i].Value {
+			case "rulesets":
 				c.rulesetsSet = true
-				break
+			case "data":
+				c.dataSet = true
+			case "include":
+				c.includeSet = true
+			case "exclude":
+				c.excludeSet = true
 			}
 		}
 	}
@@ -67,12 +102,18 @@ type ConfigExportSpec struct {
 }
 
 type ConfigLintSpec struct {
-	XunitReport string                      `yaml:"xunitReport"`
-	JSONFile    string                      `yaml:"jsonFile"`
-	IgnoreNoqa  *bool                       `yaml:"ignoreNoqa"`
-	Concurrency *int                        `yaml:"concurrency"`
-	RegoTrace   *bool                       `yaml:"regoTrace"`
-	Skip        map[string][]ConfigSkipRule `yaml:"skip"`
+	XunitReport           string                      `yaml:"xunitReport"`
+	JSONFile              string                      `yaml:"jsonFile"`
+	SarifFile             string                      `yaml:"sarifFile"`
+	Baseline              string                      `yaml:"baseline"`
+	FailOn                string                      `yaml:"failOn"`
+	FailOnRuleError       *bool                       `yaml:"failOnRuleError"`
+	IgnoreNoqa            *bool                       `yaml:"ignoreNoqa"`
+	Concurrency           *int                        `yaml:"concurrency"`
+	RegoTrace             *bool                       `yaml:"regoTrace"`
+	RuleTimeout           string                      `yaml:"ruleTimeout"`
+	Skip                  map[string][]ConfigSkipRule `yaml:"skip"`
+	SkipExpiryWarningDays *int                        `yaml:"skipExpiryWarningDays"`
 }
 
 type ConfigCacheSpec struct {
@@ -86,9 +127,10 @@ type ConfigServeSpec struct {
 }
 
 type ConfigSkipRule struct {
-	Rule   string `yaml:"rule"`
-	Reason string `yaml:"reason"`
-	Date   string `yaml:"date"`
+	Rule    string `yaml:"rule"`
+	Reason  string `yaml:"reason"`
+	Date    string `yaml:"date"`
+	Expires string `yaml:"expires"`
 }
 
 type ConfigSourceStatus struct {
@@ -108,6 +150,10 @@ type ConfigLoadReport struct {
 var activeConfig = struct {
 	mu     sync.RWMutex
 	config *Config
+	// skipPaths are the lint.skip keys of config in precedence order. They
+	// are ordered once when the config is set rather than for every
+	// (document, rule) pair.
+	skipPaths []string
 }{
 	config: &Config{},
 }
@@ -141,9 +187,11 @@ func SetConfig(config *Config) {
 	defer activeConfig.mu.Unlock()
 	if config == nil {
 		activeConfig.config = &Config{}
+		activeConfig.skipPaths = nil
 		return
 	}
 	activeConfig.config = config
+	activeConfig.skipPaths = orderedSkipPaths(config.Lint.Skip)
 }
 
 func getConfig() *Config {
@@ -155,6 +203,17 @@ func getConfig() *Config {
 	return activeConfig.config
 }
 
+// getSkipConfig returns the active config with its lint.skip keys in
+// precedence order.
+func getSkipConfig() (*Config, []string) {
+	activeConfig.mu.RLock()
+	defer activeConfig.mu.RUnlock()
+	if activeConfig.config == nil {
+		return &Config{}, nil
+	}
+	return activeConfig.config, activeConfig.skipPaths
+}
+
 func LoadMergedConfig(projectDir string) (*Config, error) {
 	cfg, _, err := LoadMergedConfigWithReport(projectDir)
 	return cfg, err
@@ -301,6 +360,33 @@ func mergeConfig(base *Config, overlay *Config) {
 	if overlay.Rules.rulesetsSet {
 		base.Rules.Rulesets = append([]string{}, overlay.Rules.Rulesets...)
 	}
+	if overlay.Rules.dataSet {
+		base.Rules.Data = append([]string{}, overlay.Rules.Data...)
+	}
+	if overlay.Rules.includeSet {
+		base.Rules.Include = append([]ConfigRuleSelector{}, overlay.Rules.Include...)
+	}
+	if overlay.Rules.excludeSet {
+		base.Rules.Exclude = append([]ConfigRuleSelector{}, overlay.Rules.Exclude...)
+	}
+	if overlay.Rules.MinSeverity != "" {
+		base.Rules.MinSeverity = strings.TrimSpace(overlay.Rules.MinSeverity)
+	}
+	if overlay.Rules.CoverageThreshold != nil {
+		base.Rules.CoverageThreshold = overlay.Rules.CoverageThreshold
+	}
+	for ruleNumber, override := range overlay.Rules.Overrides {
+		if base.Rules.Overrides == nil {
+			base.Rules.Overrides = map[string]ConfigRuleOverride{}
+		}
+		base.Rules.Overrides[ruleNumber] = base.Rules.Overrides[ruleNumber].merge(override)
+	}
+	for ruleNumber, settings := range overlay.Rules.Settings {
+		if base.Rules.Settings == nil {
+			base.Rules.Settings = map[string]map[string]interface{}{}
+		}
+		base.Rules.Settings[ruleNumber] = mergeRuleSettings(base.Rules.Settings[ruleNumber], settings)
+	}
 
 	if overlay.Export.Filter != "" {
 		base.Export.Filter = strings.TrimSpace(overlay.Export.Filter)
@@ -327,6 +413,18 @@ func mergeConfig(base *Config, overlay *Config) {
 	if overlay.Lint.JSONFile != "" {
 		base.Lint.JSONFile = strings.TrimSpace(overlay.Lint.JSONFile)
 	}
+	if overlay.Lint.SarifFile != "" {
+		base.Lint.SarifFile = strings.TrimSpace(overlay.Lint.SarifFile)
+	}
+	if overlay.Lint.Baseline != "" {
+		base.Lint.Baseline = strings.TrimSpace(overlay.Lint.Baseline)
+	}
+	if overlay.Lint.FailOn != "" {
+		base.Lint.FailOn = strings.TrimSpace(overlay.Lint.FailOn)
+	}
+	if overlay.Lint.FailOnRuleError != nil {
+		base.Lint.FailOnRuleError = overlay.Lint.FailOnRuleError
+	}
 	if overlay.Lint.IgnoreNoqa != nil {
 		base.Lint.IgnoreNoqa = overlay.Lint.IgnoreNoqa
 	}
@@ -336,6 +434,13 @@ func mergeConfig(base *Config, overlay *Config) {
 	if overlay.Lint.RegoTrace != nil {
 		base.Lint.RegoTrace = overlay.Lint.RegoTrace
 	}
+	if strings.TrimSpace(overlay.Lint.RuleTimeout) != "" {
+		base.Lint.RuleTimeout = strings.TrimSpace(overlay.Lint.RuleTimeout)
+	}
+
+	if overlay.Lint.SkipExpiryWarningDays != nil {
+		base.Lint.SkipExpiryWarningDays = overlay.Lint.SkipExpiryWarningDays
+	}
 
 	if overlay.Serve.Port != nil {
 		base.Serve.Port = overlay.Serve.Port
@@ -364,6 +469,9 @@ func mergeConfig(base *Config, overlay *Config) {
 
 func matchConfigSkipRules(entries []ConfigSkipRule, ruleNumber string) (bool, string) {
 	for _, entry := range entries {
+		if skipEntryExpired(entry) {
+			continue
+		}
 		if entry.Rule == "" || entry.Rule == "*" || entry.Rule == ruleNumber {
 			return true, formatConfigSkipReason(entry)
 		}
@@ -372,36 +480,35 @@ func matchConfigSkipRules(entries []ConfigSkipRule, ruleNumber string) (bool, st
 }
 
 func shouldSkipByConfig(inputFilePath string, ruleNumber string, modelSourcePath string) (bool, string) {
-	cfg := getConfig()
-	if cfg == nil || len(cfg.Lint.Skip) == 0 {
+	cfg, skipPaths := getSkipConfig()
+	if len(skipPaths) == 0 {
 		return false, ""
 	}
 
-	for _, candidate := range buildSkipPathCandidates(inputFilePath, modelSourcePath) {
-		entries, ok := cfg.Lint.Skip[candidate]
-		if !ok {
+	document := newSkipDocument(inputFilePath, modelSourcePath)
+	for _, skipPath := range skipPaths {
+		if !document.matches(skipPath) {
 			continue
 		}
-		if skip, reason := matchConfigSkipRules(entries, ruleNumber); skip {
+		if skip, reason := matchConfigSkipRules(cfg.Lint.Skip[skipPath], ruleNumber); skip {
 			return true, reason
 		}
 	}
 
-	if entries, ok := cfg.Lint.Skip[skipPathAllDocuments]; ok {
-		return matchConfigSkipRules(entries, ruleNumber)
-	}
-
 	return false, ""
 }
 
 func formatConfigSkipReason(entry ConfigSkipRule) string {
+	reason := "Skipped by lint.skip config"
 	if strings.TrimSpace(entry.Reason) != "" {
-		return entry.Reason
+		reason = entry.Reason
+	} else if strings.TrimSpace(entry.Date) != "" {
+		reason = fmt.Sprintf("Skipped by lint.skip config (%s)", strings.TrimSpace(entry.Date))
 	}
-	if strings.TrimSpace(entry.Date) != "" {
-		return fmt.Sprintf("Skipped by lint.skip config (%s)", strings.TrimSpace(entry.Date))
+	if expires := strings.TrimSpace(entry.Expires); expires != "" {
+		reason = fmt.Sprintf("%s (expires %s)", reason, expires)
 	}
-	return "Skipped by lint.skip config"
+	return reason
 }
 
 func buildSkipPathCandidates(inputFilePath string, modelSourcePath string) []string {
@@ -433,6 +540,9 @@ func buildSkipPathCandidates(inputFilePath string, modelSourcePath string) []str
 }
 
 func normalizeSkipPath(path string) string {
+	if isSkipSelector(strings.TrimSpace(path)) {
+		return strings.TrimSpace(path)
+	}
 	normalized := filepath.ToSlash(filepath.Clean(strings.TrimSpace(path)))
 	normalized = strings.TrimPrefix(normalized, "./")
 	normalized = strings.TrimPrefix(normalized, "/")
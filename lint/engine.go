@@ -0,0 +1,325 @@
+package lint
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// compiledRule is a rule prepared once per run and evaluated per document.
+// Implementations are safe for concurrent use.
+type compiledRule interface {
+	evalDocument(document *modelDocument, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error)
+}
+
+func compileRule(rule Rule) (compiledRule, error) {
+	settings := ruleSettings(rule)
+	switch rule.Language {
+	case LanguageRego:
+		return prepareRegoRule(rule.Path, "data."+rule.PackageName, settings)
+	case LanguageJavascript:
+		return prepareJavascriptRule(rule.Path, settings)
+	case LanguageTypescript:
+		return prepareTypescriptRule(rule.Path, settings)
+	case LanguageYaml:
+		return prepareYamlRule(rule.Path, settings)
+	case LanguageJSONSchema:
+		return prepareSchemaRule(rule.Path)
+	}
+	return nil, fmt.Errorf("unsupported language %q for rule %s", rule.Language, rule.Path)
+}
+
+// lintRun holds the state shared by every work item of one lint run.
+type lintRun struct {
+	index           *modelIndex
+	modelSourcePath string
+	ignoreNoqa      bool
+	useCache        bool
+	changedFiles    []string
+	originalPathMap map[string]string
+	configHash      string
+}
+
+func newLintRun(modelSourcePath string, ignoreNoqa bool, useCache bool, changedFiles []string, originalPathMap map[string]string) (*lintRun, error) {
+	index, err := buildModelIndex(modelSourcePath)
+	if err != nil {
+		return nil, err
+	}
+	return &lintRun{
+		index:           index,
+		modelSourcePath: modelSourcePath,
+		ignoreNoqa:      ignoreNoqa,
+		useCache:        useCache,
+		changedFiles:    changedFiles,
+		originalPathMap: originalPathMap,
+		configHash:      computeCacheConfigHash(),
+	}, nil
+}
+
+// lintSuite collects the testcases of one rule while its work items run.
+type lintSuite struct {
+	rule      Rule
+	documents []*modelDocument
+	testcases []Testcase
+	// metadata is Metadata.yaml, which is part of the input of project rules.
+	metadata  *modelDocument
+	remaining atomic.Int32
+
+	// ruleHash is empty when the rule file could not be hashed, which
+	// disables caching for the rule.
+	ruleHash string
+
+	compileOnce sync.Once
+	compiled    compiledRule
+	compileErr  error
+}
+
+// compile compiles the rule on first use, so a rule served entirely from
+// cache is never compiled. A rule that fails to compile is logged once and
+// reported as a rule error on each of its documents.
+func (s *lintSuite) compile() (compiledRule, error) {
+	s.compileOnce.Do(func() {
+		s.compiled, s.compileErr = compileRule(s.rule)
+		if s.compileErr != nil {
+			s.compileErr = newRuleEvalError(ruleErrorLoad, s.compileErr)
+			log.Errorf("Failed to load rule %s: %v", s.rule.Path, s.compileErr)
+		}
+	})
+	return s.compiled, s.compileErr
+}
+
+func (s *lintSuite) testsuite() *Testsuite {
+	failuresCount := 0
+	errorsCount := 0
+	skippedCount := 0
+	totalTime := 0.0
+	for _, testcase := range s.testcases {
+		if testcase.Failure != nil {
+			failuresCount++
+		}
+		if testcase.Error != nil {
+			errorsCount++
+		}
+		if testcase.Skipped != nil {
+			skippedCount++
+		}
+		totalTime += testcase.Time
+	}
+	return &Testsuite{
+		Name:      s.rule.Path,
+		Tests:     len(s.testcases),
+		Failures:  failuresCount,
+		Errors:    errorsCount,
+		Skipped:   skippedCount,
+		Time:      totalTime,
+		Testcases: s.testcases,
+	}
+}
+
+// lintWorkItem is one (document, rule) pair. Items of project-scoped rules
+// have no document and evaluate the rule once over all its documents.
+type lintWorkItem struct {
+	suite    int
+	testcase int
+	document *modelDocument
+}
+
+// evalTestsuites evaluates rules against the modelsource index with a bounded
+// worker pool over (document, rule) work items. Work items are ordered by
+// document so each document is parsed once, shared by every rule matching it
+// and released when the last of those rules is done. done is called once per
+// testsuite as soon as its last work item finishes; it may be called
+// concurrently. Testsuites are returned in rule order with testcases in
+// modelsource walk order.
+func (r *lintRun) evalTestsuites(rules []Rule, done func(index int, testsuite *Testsuite)) ([]Testsuite, error) {
+	changedSet := normalizeChangedFilesSet(r.changedFiles)
+	suites := make([]*lintSuite, len(rules))
+	documentItems := map[*modelDocument][]lintWorkItem{}
+	projectItems := make([]lintWorkItem, 0)
+
+	for i, rule := range rules {
+		log.Debugf("evaluating rule %s", rule.Path)
+		suite := &lintSuite{rule: rule}
+		suites[i] = suite
+
+		documents, err := r.index.match(rule.Pattern)
+		if err != nil {
+			// A rule with an invalid input pattern has no documents; it is
+			// reported as a rule error while the other rules carry on.
+			ruleError := &RuleError{Message: fmt.Sprintf("Rule %s could not be loaded: %v", rule.Path, err), Type: ruleErrorLoad}
+			log.Error(ruleError.Message)
+			suite.testcases = []Testcase{{Name: rule.Path, Error: ruleError}}
+			continue
+		}
+
+		if rule.Scope == ScopeProject {
+			// The project rule filters changed files itself, because errors on
+			// changed documents may come from unchanged ones.
+			suite.documents = documents
+			for _, document := range documents {
+				document.retain()
+			}
+			if metadata := r.index.document(projectMetadataDocument); metadata != nil {
+				metadata.retain()
+				suite.metadata = metadata
+			}
+			suite.remaining.Store(1)
+			projectItems = append(projectItems, lintWorkItem{suite: i})
+			continue
+		}
+
+		if changedSet != nil {
+			filtered := make([]*modelDocument, 0, len(documents))
+			for _, document := range documents {
+				if _, ok := changedSet[cleanPath(document.path)]; ok {
+					filtered = append(filtered, document)
+				}
+			}
+			documents = filtered
+		}
+		suite.documents = documents
+		suite.testcases = make([]Testcase, len(documents))
+		suite.remaining.Store(int32(len(documents)))
+		if ruleHash, err := computeRuleHash(rule.Path); err != nil {
+			log.Debugf("Error creating cache key: %v", err)
+		} else {
+			suite.ruleHash = ruleHash
+		}
+		for j, document := range documents {
+			document.retain()
+			documentItems[document] = append(documentItems[document], lintWorkItem{suite: i, testcase: j, document: document})
+		}
+	}
+
+	items := projectItems
+	for _, document := range r.index.documents {
+		items = append(items, documentItems[document]...)
+	}
+
+	testsuites := make([]Testsuite, len(rules))
+	finish := func(index int, testsuite *Testsuite) {
+		if done != nil {
+			done(index, testsuite)
+		}
+		testsuites[index] = *testsuite
+	}
+	for i, suite := range suites {
+		if suite.remaining.Load() == 0 {
+			finish(i, suite.testsuite())
+		}
+	}
+
+	var failed atomic.Bool
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+		})
+		failed.Store(true)
+	}
+
+	queue := make(chan lintWorkItem)
+	var wg sync.WaitGroup
+	for w := 0; w < effectiveLintConcurrency(len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				suite := suites[item.suite]
+				if item.document == nil {
+					testsuite, err := r.evalProjectItem(suite)
+					if err != nil {
+						fail(err)
+						continue
+					}
+					finish(item.suite, testsuite)
+					continue
+				}
+
+				testcase, err := r.evalWorkItem(suite, item.document)
+				item.document.release()
+				if err != nil {
+					fail(err)
+					continue
+				}
+				suite.testcases[item.testcase] = *testcase
+				if suite.remaining.Add(-1) == 0 {
+					finish(item.suite, suite.testsuite())
+				}
+			}
+		}()
+	}
+	for _, item := range items {
+		if failed.Load() {
+			break
+		}
+		queue <- item
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return testsuites, nil
+}
+
+func (r *lintRun) evalProjectItem(suite *lintSuite) (*Testsuite, error) {
+	defer func() {
+		for _, document := range suite.documents {
+			document.release()
+		}
+		if suite.metadata != nil {
+			suite.metadata.release()
+		}
+	}()
+	return evalProjectTestsuite(suite.rule, r, suite.documents, suite.metadata)
+}
+
+// evalWorkItem evaluates one rule against one document, using the cache when
+// enabled. Results are not cached when ignoreNoqa is set because they might
+// differ from the normal behavior.
+func (r *lintRun) evalWorkItem(suite *lintSuite, document *modelDocument) (*Testcase, error) {
+	var cacheKey *CacheKey
+	if suite.ruleHash != "" {
+		if inputHash, err := document.contentHash(); err != nil {
+			log.Debugf("Error creating cache key: %v", err)
+		} else {
+			cacheKey = &CacheKey{RuleHash: suite.ruleHash, InputHash: inputHash, ConfigHash: r.configHash}
+		}
+	}
+	cacheable := cacheKey != nil && r.useCache && !r.ignoreNoqa
+
+	var testcase *Testcase
+	if cacheable {
+		if cachedTestcase, found := loadCachedTestcase(*cacheKey); found {
+			log.Debugf("Using cached result for %s", document.path)
+			testcase = cachedTestcase
+		}
+	}
+	if testcase == nil {
+		compiled, err := suite.compile()
+		if err != nil {
+			testcase = newRuleErrorTestcase(suite.rule.Path, document.path, err, 0)
+		} else {
+			testcase, err = compiled.evalDocument(document, suite.rule.RuleNumber, r.ignoreNoqa, r.modelSourcePath)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Timeouts depend on the machine and its load, and rule errors are
+		// expected to be fixed, so neither is cached.
+		if cacheable && testcase.Error == nil && (testcase.Failure == nil || testcase.Failure.Type != failureTypeTimeout) {
+			if cacheErr := saveCachedTestcase(*cacheKey, testcase); cacheErr != nil {
+				log.Debugf("Error saving to cache: %v", cacheErr)
+				// Don't fail the evaluation if cache save fails
+			}
+		}
+	}
+
+	// Normalize testcase name for output consistency regardless of cache source.
+	testcase.Name = formatTestcaseName(document.path, r.modelSourcePath)
+	testcase.OriginalPath = resolveOriginalPath(testcase.Name, r.originalPathMap)
+	return testcase, nil
+}
@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestEvalTestsuites_SharesDocumentsAcrossRules(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	modelDir := t.TempDir()
+	for _, name := range []string{"A.yaml", "B.yaml", "C.yaml"} {
+		if err := writeTestFile(modelDir, name, "Name: Original\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	mutating := Rule{
+		Path:       writeTestRule(t, "mutate.js", "function rule(input) { input.Name = \"Changed\"; return { allow: true, errors: [] }; }"),
+		RuleNumber: "001_0001",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJavascript,
+	}
+	checking := Rule{
+		Path:       writeTestRule(t, "check.js", "function rule(input) { const errors = input.Name === \"Original\" ? [] : [\"shared input was modified\"]; return { allow: errors.length === 0, errors: errors }; }"),
+		RuleNumber: "001_0002",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJavascript,
+	}
+	onlyB := Rule{
+		Path:        writeTestRule(t, "only_b.rego", "package test.only_b\n\nimport rego.v1\n\ndefault allow := false\nallow if input.Name == \"Original\"\nerrors := []\n"),
+		RuleNumber:  "001_0003",
+		Pattern:     ".*B\\.yaml",
+		PackageName: "test.only_b",
+		Language:    LanguageRego,
+	}
+
+	for _, concurrency := range []int{1, 4} {
+		SetConfig(&Config{Lint: ConfigLintSpec{Concurrency: &concurrency}})
+		run, err := newLintRun(modelDir, false, false, nil, nil)
+		if err != nil {
+			t.Fatalf("Failed to index modelsource: %v", err)
+		}
+		testsuites, err := run.evalTestsuites([]Rule{mutating, checking, onlyB}, nil)
+		if err != nil {
+			t.Fatalf("evalTestsuites returned error: %v", err)
+		}
+
+		if len(testsuites) != 3 || testsuites[0].Name != mutating.Path || testsuites[1].Name != checking.Path || testsuites[2].Name != onlyB.Path {
+			t.Fatalf("expected testsuites in rule order, got %+v", testsuites)
+		}
+		if testsuites[1].Failures != 0 {
+			t.Fatalf("expected rules not to see each other's changes, got %+v", testsuites[1].Testcases)
+		}
+		names := []string{}
+		for _, tc := range testsuites[0].Testcases {
+			names = append(names, tc.Name)
+		}
+		if len(names) != 3 || names[0] != "A.yaml" || names[1] != "B.yaml" || names[2] != "C.yaml" {
+			t.Fatalf("expected testcases in walk order, got %v", names)
+		}
+		if testsuites[2].Tests != 1 || testsuites[2].Failures != 0 {
+			t.Fatalf("expected Rego rule to pass on its single document, got %+v", testsuites[2])
+		}
+	}
+}
+
+func TestEvalTestsuites_EmptyRuleStillReported(t *testing.T) {
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "A.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	rule := Rule{
+		Path:     writeTestRule(t, "none.js", "function rule(input) { return { allow: false, errors: [\"unexpected\"] }; }"),
+		Pattern:  ".*\\.json",
+		Language: LanguageJavascript,
+	}
+
+	run, err := newLintRun(modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to index modelsource: %v", err)
+	}
+	reported := 0
+	testsuites, err := run.evalTestsuites([]Rule{rule}, func(index int, testsuite *Testsuite) {
+		reported++
+	})
+	if err != nil {
+		t.Fatalf("evalTestsuites returned error: %v", err)
+	}
+	if reported != 1 || testsuites[0].Name != rule.Path || testsuites[0].Tests != 0 {
+		t.Fatalf("expected one empty testsuite, got %d reported and %+v", reported, testsuites)
+	}
+}
+
+func TestEvalTestsuites_InvalidInputPattern(t *testing.T) {
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "A.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	invalid := Rule{
+		Path:     writeTestRule(t, "invalid.js", "function rule(input) { return { allow: true, errors: [] }; }"),
+		Pattern:  "(",
+		Language: LanguageJavascript,
+	}
+	valid := Rule{
+		Path:     writeTestRule(t, "valid.js", "function rule(input) { return { allow: false, errors: [\"failed\"] }; }"),
+		Pattern:  ".*\\.yaml",
+		Language: LanguageJavascript,
+	}
+
+	run, err := newLintRun(modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to index modelsource: %v", err)
+	}
+	testsuites, err := run.evalTestsuites([]Rule{invalid, valid}, nil)
+	if err != nil {
+		t.Fatalf("expected an invalid input pattern not to stop the run, got: %v", err)
+	}
+	if testsuites[0].Errors != 1 || testsuites[0].Testcases[0].Error.Type != ruleErrorLoad {
+		t.Fatalf("expected a LoadError for the invalid pattern, got %+v", testsuites[0])
+	}
+	if testsuites[1].Tests != 1 || testsuites[1].Failures != 1 {
+		t.Fatalf("expected the other rule to be evaluated, got %+v", testsuites[1])
+	}
+}
+
+func TestModelDocument_ReleaseDropsParsedValue(t *testing.T) {
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "A.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	document := newModelDocument(filepath.Join(modelDir, "A.yaml"), "A.yaml")
+	document.retain()
+	document.retain()
+
+	first, _, err := document.yamlDocument()
+	if err != nil {
+		t.Fatalf("Failed to parse document: %v", err)
+	}
+	second, _, _ := document.yamlDocument()
+	if first["Name"] != "Test" || second["Name"] != "Test" {
+		t.Fatalf("unexpected document content: %v", first)
+	}
+
+	document.release()
+	if document.data == nil {
+		t.Fatal("expected parsed document to be kept while a rule holds it")
+	}
+	document.release()
+	if document.data != nil || document.node != nil {
+		t.Fatal("expected parsed document to be dropped after the last release")
+	}
+}
+
+func writeTestRule(t *testing.T, name string, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := writeTestFile(dir, name, content); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	return filepath.Join(dir, name)
+}
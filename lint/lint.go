@@ -23,6 +23,15 @@ func printTestsuite(ts Testsuite) {
 		if tc.Skipped != nil {
 			result = "SKIP"
 		}
+		if tc.Warning != nil {
+			result = "WARN"
+		}
+		if tc.Failure == nil && tc.Baselined != nil {
+			result = "BASE"
+		}
+		if tc.Error != nil {
+			result = "ERROR"
+		}
 		fmt.Printf("%s (%.5fs) %s\n", result, tc.Time, tc.Name)
 	}
 	fmt.Println("")
@@ -30,201 +39,210 @@ func printTestsuite(ts Testsuite) {
 
 // EvalAllWithResults evaluates all rules and returns the results
 // This is similar to EvalAll but returns the results instead of just printing them
-func EvalAllWithResults(rulesPath string, modelSourcePath string, xunitReport string, jsonFile string, ignoreNoqa bool, useCache bool, changedFiles []string) (interface{}, error) {
-	rules, err := ReadRulesMetadata(rulesPath)
+func EvalAllWithResults(rulesPath string, modelSourcePath string, xunitReport string, jsonFile string, sarifFile string, ignoreNoqa bool, useCache bool, changedFiles []string) (interface{}, error) {
+	rules, testsuites, err := evalAll(rulesPath, modelSourcePath, ignoreNoqa, useCache, changedFiles)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a slice to store results in order
-	testsuites := make([]Testsuite, len(rules))
-
-	// Use a WaitGroup to synchronize goroutines
-	var wg sync.WaitGroup
-
-	// Create a channel to collect errors
-	errChan := make(chan error, len(rules))
-
-	// Create a mutex to safely print testsuites
-	var printMutex sync.Mutex
-
-	originalPathMap := loadOriginalPathMap(modelSourcePath)
-
-	maxConcurrency := effectiveLintConcurrency(len(rules))
-	sem := make(chan struct{}, maxConcurrency)
-
-	// Launch goroutines to evaluate rules in parallel
-	for i, rule := range rules {
-		sem <- struct{}{}
-		wg.Add(1)
-		go func(index int, r Rule) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			testsuite, err := evalTestsuite(r, modelSourcePath, ignoreNoqa, useCache, changedFiles, originalPathMap)
-			if err != nil {
-				errChan <- err
-				return
-			}
-
-			// Print with mutex to avoid interleaved output
-			printMutex.Lock()
-			printTestsuite(*testsuite)
-			printMutex.Unlock()
-
-			testsuites[index] = *testsuite
-		}(i, rule)
+	if err := writeReports(testsuites, rules, modelSourcePath, xunitReport, jsonFile, sarifFile); err != nil {
+		return nil, err
 	}
 
-	// Wait for all goroutines to complete
-	wg.Wait()
-	close(errChan)
+	logTestsuiteFailures(testsuites)
+	logLintSummary(testsuites, rules)
 
-	// Check if any errors occurred
-	if len(errChan) > 0 {
-		return nil, <-errChan
-	}
+	// Return the results
+	testsuitesContainer := TestSuites{Testsuites: testsuites, Rules: rules}
+	return testsuitesContainer, lintRunError(countFailures(testsuites), countRuleErrors(testsuites))
+}
 
-	// Calculate total failures
-	failuresCount := 0
-	for _, ts := range testsuites {
-		failuresCount += ts.Failures
+func EvalAll(rulesPath string, modelSourcePath string, xunitReport string, jsonFile string, sarifFile string, ignoreNoqa bool, useCache bool, changedFiles []string) error {
+	rules, testsuites, err := evalAll(rulesPath, modelSourcePath, ignoreNoqa, useCache, changedFiles)
+	if err != nil {
+		return err
 	}
 
-	if xunitReport != "" {
-		file, err := os.Create(xunitReport)
-		if err != nil {
-			panic(err)
-		}
-		defer file.Close()
-
-		encoder := xml.NewEncoder(file)
-		encoder.Indent("", "  ")
-		testsuitesContainer := TestSuites{Testsuites: testsuites}
-		if err := encoder.Encode(testsuitesContainer); err != nil {
-			panic(err)
-		}
+	if err := writeReports(testsuites, rules, modelSourcePath, xunitReport, jsonFile, sarifFile); err != nil {
+		return err
 	}
 
-	if jsonFile != "" {
-		file, err := os.Create(jsonFile)
-		if err != nil {
-			panic(err)
-		}
-		defer file.Close()
+	logTestsuiteFailures(testsuites)
+	logLintSummary(testsuites, rules)
+	return lintRunError(countFailures(testsuites), countRuleErrors(testsuites))
+}
 
-		encoder := json.NewEncoder(file)
-		encoder.SetIndent("", "  ")
-		testsuitesContainer := TestSuites{Testsuites: testsuites, Rules: rules}
-		if err := encoder.Encode(testsuitesContainer); err != nil {
-			panic(err)
+// logLintSummary logs the failures and rule errors per rule, followed by the
+// severity and baseline summaries.
+func logLintSummary(testsuites []Testsuite, rules []Rule) {
+	failuresCount := countFailures(testsuites)
+	ruleErrorsCount := countRuleErrors(testsuites)
+	if failuresCount > 0 {
+		log.Errorf("Lint summary: Found %d failures:", failuresCount)
+		log.Errorf("Failures by rule:")
+		for _, ts := range testsuites {
+			if ts.Failures > 0 {
+				log.Errorf("- %s: %d failures", ts.Name, ts.Failures)
+			}
 		}
 	}
-
-	for _, ts := range testsuites {
-		if ts.Failures > 0 {
-			log.Warningf("Rule %s: %d failures", ts.Name, ts.Failures)
-			for _, tc := range ts.Testcases {
-				if tc.Failure != nil {
-					log.Warningf("  Document %s: %s", tc.Name, tc.Failure.Message)
-				}
+	if ruleErrorsCount > 0 {
+		log.Errorf("Lint summary: Found %d rule errors:", ruleErrorsCount)
+		log.Errorf("Rule errors by rule:")
+		for _, ts := range testsuites {
+			if ts.Errors > 0 {
+				log.Errorf("- %s: %d rule errors", ts.Name, ts.Errors)
 			}
 		}
+		if !failOnRuleError() {
+			log.Warnf("Rule errors do not fail lint because lint.failOnRuleError is false")
+		}
 	}
-
-	// Return the results
-	testsuitesContainer := TestSuites{Testsuites: testsuites, Rules: rules}
-
-	if failuresCount > 0 {
-		return testsuitesContainer, fmt.Errorf("%d failures", failuresCount)
-	} else {
+	if failuresCount == 0 && ruleErrorsCount == 0 {
 		log.Infof("Lint summary: All rules passed successfully!")
 		log.Infof("Total rules evaluated: %d", len(rules))
 		log.Infof("Total files checked: %d", countTotalTestcases(testsuites))
 	}
-	return testsuitesContainer, nil
+	logSeveritySummary(testsuites, rules)
+	logBaselineSummary(testsuites)
 }
 
-func EvalAll(rulesPath string, modelSourcePath string, xunitReport string, jsonFile string, ignoreNoqa bool, useCache bool, changedFiles []string) error {
-	rules, err := ReadRulesMetadata(rulesPath)
-	if err != nil {
-		return err
+// lintRunError returns the error that fails the lint run, or nil. Rule errors
+// fail the run unless lint.failOnRuleError is false.
+func lintRunError(failuresCount int, ruleErrorsCount int) error {
+	if !failOnRuleError() {
+		ruleErrorsCount = 0
 	}
+	switch {
+	case failuresCount > 0 && ruleErrorsCount > 0:
+		return fmt.Errorf("%d failures, %d rule errors", failuresCount, ruleErrorsCount)
+	case failuresCount > 0:
+		return fmt.Errorf("%d failures", failuresCount)
+	case ruleErrorsCount > 0:
+		return fmt.Errorf("%d rule errors", ruleErrorsCount)
+	}
+	return nil
+}
 
-	// Create a slice to store results in order
-	testsuites := make([]Testsuite, len(rules))
-
-	// Use a WaitGroup to synchronize goroutines
-	var wg sync.WaitGroup
+// resultPolicy decides how raw rule results are reported. It is applied to
+// each testsuite after evaluation, so cached testcases stay policy-free.
+type resultPolicy struct {
+	baseline *lintBaseline
+	failOn   string
+}
 
-	// Create a channel to collect errors
-	errChan := make(chan error, len(rules))
+func (p resultPolicy) apply(testsuite *Testsuite, rule Rule) {
+	p.baseline.apply(testsuite, rule)
+	applyFailOnThreshold(testsuite, rule, p.failOn)
+}
 
-	// Create a mutex to safely print testsuites
-	var printMutex sync.Mutex
+// loadResultPolicy builds the result policy from the active config.
+func loadResultPolicy() (resultPolicy, error) {
+	failOn := failOnThreshold()
+	if failOn != "" {
+		if err := validateSeverity(failOn); err != nil {
+			return resultPolicy{}, fmt.Errorf("invalid lint.failOn: %w", err)
+		}
+	}
 
-	originalPathMap := loadOriginalPathMap(modelSourcePath)
+	baseline, err := loadConfiguredBaseline()
+	if err != nil {
+		return resultPolicy{}, err
+	}
+	return resultPolicy{baseline: baseline, failOn: failOn}, nil
+}
 
-	maxConcurrency := effectiveLintConcurrency(len(rules))
-	sem := make(chan struct{}, maxConcurrency)
+// evalAll reads the rules and evaluates them in parallel. Testsuites are
+// returned in rule order with the configured result policy already applied.
+func evalAll(rulesPath string, modelSourcePath string, ignoreNoqa bool, useCache bool, changedFiles []string) ([]Rule, []Testsuite, error) {
+	rules, err := prepareRules(rulesPath)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Launch goroutines to evaluate rules in parallel
-	for i, rule := range rules {
-		sem <- struct{}{}
-		wg.Add(1)
-		go func(index int, r Rule) {
-			defer wg.Done()
-			defer func() { <-sem }()
+	policy, err := loadResultPolicy()
+	if err != nil {
+		return nil, nil, err
+	}
 
-			testsuite, err := evalTestsuite(r, modelSourcePath, ignoreNoqa, useCache, changedFiles, originalPathMap)
-			if err != nil {
-				errChan <- err
-				return
-			}
+	testsuites, err := evalRules(rules, modelSourcePath, ignoreNoqa, useCache, changedFiles, policy)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rules, testsuites, nil
+}
 
-			// Print with mutex to avoid interleaved output
-			printMutex.Lock()
-			printTestsuite(*testsuite)
-			printMutex.Unlock()
+// prepareRules reads the rules and their data, and validates the lint
+// configuration the rules are evaluated with.
+func prepareRules(rulesPath string) ([]Rule, error) {
+	rules, err := loadRules(rulesPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadRulesData(rulesPath); err != nil {
+		return nil, err
+	}
+	if cfg := getConfig(); cfg != nil {
+		if _, err := parseRuleTimeout(cfg.Lint.RuleTimeout); err != nil {
+			return nil, err
+		}
+		if err := validateSkipEntries(cfg.Lint.Skip); err != nil {
+			return nil, err
+		}
+	}
+	logSkipExpiry()
+	return rules, nil
+}
 
-			testsuites[index] = *testsuite
-		}(i, rule)
+func evalRules(rules []Rule, modelSourcePath string, ignoreNoqa bool, useCache bool, changedFiles []string, policy resultPolicy) ([]Testsuite, error) {
+	run, err := newLintRun(modelSourcePath, ignoreNoqa, useCache, changedFiles, loadOriginalPathMap(modelSourcePath))
+	if err != nil {
+		return nil, err
 	}
+	warnUnmatchedSkipPaths(run.index)
 
-	// Wait for all goroutines to complete
-	wg.Wait()
-	close(errChan)
+	// Create a mutex to safely print testsuites
+	var printMutex sync.Mutex
 
-	// Check if any errors occurred
-	if len(errChan) > 0 {
-		return <-errChan
-	}
+	return run.evalTestsuites(rules, func(index int, testsuite *Testsuite) {
+		policy.apply(testsuite, rules[index])
 
-	// Calculate total failures
-	failuresCount := 0
-	for _, ts := range testsuites {
-		failuresCount += ts.Failures
-	}
+		// Print with mutex to avoid interleaved output
+		printMutex.Lock()
+		printTestsuite(*testsuite)
+		printMutex.Unlock()
+	})
+}
 
+func writeReports(testsuites []Testsuite, rules []Rule, modelSourcePath string, xunitReport string, jsonFile string, sarifFile string) error {
 	if xunitReport != "" {
 		file, err := os.Create(xunitReport)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("failed to write xunit report %s: %w", xunitReport, err)
 		}
 		defer file.Close()
 
+		for i := range testsuites {
+			for j := range testsuites[i].Testcases {
+				tc := &testsuites[i].Testcases[j]
+				if tc.Failure != nil {
+					tc.Failure.Data = violationDetails(tc.Violations)
+				}
+			}
+		}
+
 		encoder := xml.NewEncoder(file)
 		encoder.Indent("", "  ")
 		testsuitesContainer := TestSuites{Testsuites: testsuites}
 		if err := encoder.Encode(testsuitesContainer); err != nil {
-			panic(err)
+			return fmt.Errorf("failed to write xunit report %s: %w", xunitReport, err)
 		}
 	}
 
 	if jsonFile != "" {
 		file, err := os.Create(jsonFile)
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("failed to write JSON report %s: %w", jsonFile, err)
 		}
 		defer file.Close()
 
@@ -232,11 +250,28 @@ func EvalAll(rulesPath string, modelSourcePath string, xunitReport string, jsonF
 		encoder.SetIndent("", "  ")
 		testsuitesContainer := TestSuites{Testsuites: testsuites, Rules: rules}
 		if err := encoder.Encode(testsuitesContainer); err != nil {
-			panic(err)
+			return fmt.Errorf("failed to write JSON report %s: %w", jsonFile, err)
 		}
 	}
 
+	if sarifFile != "" {
+		if err := writeSarifReport(sarifFile, testsuites, rules, modelSourcePath); err != nil {
+			return fmt.Errorf("failed to write SARIF report %s: %w", sarifFile, err)
+		}
+	}
+	return nil
+}
+
+func logTestsuiteFailures(testsuites []Testsuite) {
 	for _, ts := range testsuites {
+		if ts.Warnings > 0 {
+			log.Warningf("Rule %s: %d warnings", ts.Name, ts.Warnings)
+			for _, tc := range ts.Testcases {
+				if tc.Warning != nil {
+					log.Warningf("  Document %s: %s", tc.Name, tc.Warning.Message)
+				}
+			}
+		}
 		if ts.Failures > 0 {
 			log.Warningf("Rule %s: %d failures", ts.Name, ts.Failures)
 			for _, tc := range ts.Testcases {
@@ -245,146 +280,43 @@ func EvalAll(rulesPath string, modelSourcePath string, xunitReport string, jsonF
 				}
 			}
 		}
-	}
-
-	if failuresCount > 0 {
-		log.Errorf("Lint summary: Found %d failures:", failuresCount)
-		log.Errorf("Failures by rule:")
-		for _, ts := range testsuites {
-			if ts.Failures > 0 {
-				log.Errorf("- %s: %d failures", ts.Name, ts.Failures)
+		if ts.Errors > 0 {
+			log.Errorf("Rule %s: %d rule errors", ts.Name, ts.Errors)
+			for _, tc := range ts.Testcases {
+				if tc.Error != nil {
+					log.Errorf("  Document %s: %s: %s", tc.Name, tc.Error.Type, tc.Error.Message)
+				}
 			}
 		}
-		return fmt.Errorf("%d failures", failuresCount)
-	} else {
-		log.Infof("Lint summary: All rules passed successfully!")
-		log.Infof("Total rules evaluated: %d", len(rules))
-		log.Infof("Total files checked: %d", countTotalTestcases(testsuites))
 	}
-	return nil
 }
 
-// countTotalTestcases returns the total number of testcases across all testsuites
-func countTotalTestcases(testsuites []Testsuite) int {
+// countFailures returns the total number of failing testcases across all testsuites
+func countFailures(testsuites []Testsuite) int {
 	count := 0
 	for _, ts := range testsuites {
-		count += len(ts.Testcases)
+		count += ts.Failures
 	}
 	return count
 }
 
-func evalTestsuite(rule Rule, modelSourcePath string, ignoreNoqa bool, useCache bool, changedFiles []string, originalPathMap map[string]string) (*Testsuite, error) {
-
-	log.Debugf("evaluating rule %s", rule.Path)
-
-	queryString := "data." + rule.PackageName
-	testcases := make([]Testcase, 0)
-	failuresCount := 0
-	skippedCount := 0
-	totalTime := 0.0
-	inputFiles, err := expandPaths(rule.Pattern, modelSourcePath)
-	if err != nil {
-		return nil, err
-	}
-	inputFiles = filterInputFiles(inputFiles, normalizeChangedFilesSet(changedFiles))
-	testcase := &Testcase{}
-
-	for _, inputFile := range inputFiles {
-
-		// Try to load from cache first (but skip cache if ignoreNoqa is true or useCache is false)
-		cacheKey, err := createCacheKey(rule.Path, inputFile)
-		if err != nil {
-			log.Debugf("Error creating cache key: %v", err)
-		} else if useCache && !ignoreNoqa {
-			cachedTestcase, found := loadCachedTestcase(*cacheKey)
-			if found {
-				testcase = cachedTestcase
-				log.Debugf("Using cached result for %s", inputFile)
-			} else {
-				// Cache miss - evaluate and save to cache
-				testcase, err = evalTestcaseWithCaching(rule, queryString, inputFile, cacheKey, ignoreNoqa, modelSourcePath, useCache)
-				if err != nil {
-					return nil, err
-				}
-			}
-		} else {
-			// useCache is false or ignoreNoqa is true, skip cache and evaluate directly
-			testcase, err = evalTestcaseWithCaching(rule, queryString, inputFile, cacheKey, ignoreNoqa, modelSourcePath, useCache)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		// Fallback if cache key creation failed
-		if cacheKey == nil {
-			if rule.Language == LanguageRego {
-				testcase, err = evalTestcase_Rego(rule.Path, queryString, inputFile, rule.RuleNumber, ignoreNoqa, modelSourcePath)
-			} else if rule.Language == LanguageJavascript {
-				testcase, err = evalTestcase_Javascript(rule.Path, inputFile, rule.RuleNumber, ignoreNoqa, modelSourcePath)
-			} else if rule.Language == LanguageTypescript {
-				testcase, err = evalTestcase_Typescript(rule.Path, inputFile, rule.RuleNumber, ignoreNoqa, modelSourcePath)
-			}
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		// Normalize testcase name for output consistency regardless of cache source.
-		testcase.Name = formatTestcaseName(inputFile, modelSourcePath)
-		testcase.OriginalPath = resolveOriginalPath(testcase.Name, originalPathMap)
-
-		if testcase.Failure != nil {
-			failuresCount++
-		}
-
-		if testcase.Skipped != nil {
-			skippedCount++
-		}
-
-		totalTime += testcase.Time
-
-		testcases = append(testcases, *testcase)
-	}
-
-	testsuite := &Testsuite{
-		Name:      rule.Path,
-		Tests:     len(testcases),
-		Failures:  failuresCount,
-		Skipped:   skippedCount,
-		Time:      totalTime,
-		Testcases: testcases,
+// countRuleErrors returns the total number of testcases whose rule could not
+// be evaluated across all testsuites
+func countRuleErrors(testsuites []Testsuite) int {
+	count := 0
+	for _, ts := range testsuites {
+		count += ts.Errors
 	}
-
-	return testsuite, nil
+	return count
 }
 
-// evalTestcaseWithCaching evaluates a testcase and saves the result to cache
-func evalTestcaseWithCaching(rule Rule, queryString string, inputFile string, cacheKey *CacheKey, ignoreNoqa bool, modelSourcePath string, useCache bool) (*Testcase, error) {
-	var testcase *Testcase
-	var err error
-
-	if rule.Language == LanguageRego {
-		testcase, err = evalTestcase_Rego(rule.Path, queryString, inputFile, rule.RuleNumber, ignoreNoqa, modelSourcePath)
-	} else if rule.Language == LanguageJavascript {
-		testcase, err = evalTestcase_Javascript(rule.Path, inputFile, rule.RuleNumber, ignoreNoqa, modelSourcePath)
-	} else if rule.Language == LanguageTypescript {
-		testcase, err = evalTestcase_Typescript(rule.Path, inputFile, rule.RuleNumber, ignoreNoqa, modelSourcePath)
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	// Only save to cache when useCache is true and ignoreNoqa is false
-	// When ignoreNoqa is true, the result might differ from the normal behavior
-	if useCache && !ignoreNoqa {
-		if cacheErr := saveCachedTestcase(*cacheKey, testcase); cacheErr != nil {
-			log.Debugf("Error saving to cache: %v", cacheErr)
-			// Don't fail the evaluation if cache save fails
-		}
+// countTotalTestcases returns the total number of testcases across all testsuites
+func countTotalTestcases(testsuites []Testsuite) int {
+	count := 0
+	for _, ts := range testsuites {
+		count += len(ts.Testcases)
 	}
-
-	return testcase, nil
+	return count
 }
 
 func formatTestcaseName(inputFilePath string, modelSourcePath string) string {
@@ -407,31 +339,12 @@ func formatTestcaseName(inputFilePath string, modelSourcePath string) string {
 
 func ReadRulesMetadata(rulesPath string) ([]Rule, error) {
 	rules := make([]Rule, 0)
-	walkErr := filepath.Walk(rulesPath, func(path string, info os.FileInfo, err error) error {
+	walkErr := walkRuleFiles(rulesPath, func(path string, kind string, parse ruleMetadataParser) error {
+		rule, err := parse(path)
 		if err != nil {
-			return err
-		}
-		if !info.IsDir() && !strings.HasSuffix(info.Name(), "_test.rego") && strings.HasSuffix(info.Name(), ".rego") {
-			rule, err := parseRuleMetadata_Rego(path)
-			if err != nil {
-				return fmt.Errorf("failed to parse rego rule metadata for %s: %w", path, err)
-			}
-			rules = append(rules, *rule)
-		}
-		if !info.IsDir() && !strings.HasSuffix(info.Name(), "_test.js") && strings.HasSuffix(info.Name(), ".js") {
-			rule, err := parseRuleMetadata_Javascript(path)
-			if err != nil {
-				return fmt.Errorf("failed to parse javascript rule metadata for %s: %w", path, err)
-			}
-			rules = append(rules, *rule)
-		}
-		if !info.IsDir() && !strings.HasSuffix(info.Name(), "_test.ts") && strings.HasSuffix(info.Name(), ".ts") {
-			rule, err := parseRuleMetadata_Typescript(path)
-			if err != nil {
-				return fmt.Errorf("failed to parse typescript rule metadata for %s: %w", path, err)
-			}
-			rules = append(rules, *rule)
+			return fmt.Errorf("failed to parse %s rule metadata for %s: %w", kind, path, err)
 		}
+		rules = append(rules, *rule)
 		return nil
 	})
 	if walkErr != nil {
@@ -439,3 +352,41 @@ func ReadRulesMetadata(rulesPath string) ([]Rule, error) {
 	}
 	return rules, nil
 }
+
+// ruleMetadataParser reads the metadata of a rule file.
+type ruleMetadataParser func(rulePath string) (*Rule, error)
+
+// walkRuleFiles calls fn with the kind and metadata parser of every rule file
+// in rulesPath. Test modules and the data/ and lib/ directories are skipped.
+func walkRuleFiles(rulesPath string, fn func(path string, kind string, parse ruleMetadataParser) error) error {
+	setRulesRoot(rulesPath)
+	dataPath := filepath.Join(rulesPath, rulesDataDirectory)
+	libraryPath := filepath.Join(rulesPath, rulesLibraryDirectory)
+	return filepath.Walk(rulesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && (path == dataPath || path == libraryPath) {
+			return filepath.SkipDir
+		}
+		if info.IsDir() {
+			return nil
+		}
+		name := info.Name()
+		switch {
+		case !strings.HasSuffix(name, "_test.rego") && strings.HasSuffix(name, ".rego"):
+			return fn(path, "rego", parseRuleMetadata_Rego)
+		case !strings.HasSuffix(name, "_test.js") && strings.HasSuffix(name, ".js"):
+			return fn(path, "javascript", parseRuleMetadata_Javascript)
+		// Declaration files, such as those written by the types command, are not rules.
+		case !strings.HasSuffix(name, "_test.ts") && !strings.HasSuffix(name, ".d.ts") && strings.HasSuffix(name, ".ts"):
+			return fn(path, "typescript", parseRuleMetadata_Typescript)
+		// YAML files without assertions, such as ruleset configuration, are not rules.
+		case !strings.HasSuffix(name, "_test.yaml") && strings.HasSuffix(name, ".yaml") && isYamlRuleFile(path):
+			return fn(path, "yaml", parseRuleMetadata_Yaml)
+		case strings.HasSuffix(name, schemaRuleSuffix):
+			return fn(path, "json schema", parseRuleMetadata_Schema)
+		}
+		return nil
+	})
+}
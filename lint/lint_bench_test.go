@@ -0,0 +1,136 @@
+package lint
+
+import "testing"
+
+const benchmarkModelSourcePath = "./../resources/modelsource-v2"
+
+// expandPaths returns the documents in workingDirectory that match a rule pattern.
+func expandPaths(pattern string, workingDirectory string) ([]string, error) {
+	index, err := buildModelIndex(workingDirectory)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := index.match(pattern)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]string, 0, len(documents))
+	for _, document := range documents {
+		matches = append(matches, document.path)
+	}
+	return matches, nil
+}
+
+// benchmarkRules returns the per-document rules in resources/rules for the
+// given language, or all of them when language is empty.
+func benchmarkRules(b *testing.B, language string) []Rule {
+	b.Helper()
+	rules, err := ReadRulesMetadata("./../resources/rules")
+	if err != nil {
+		b.Fatalf("Failed to read rules: %v", err)
+	}
+	selected := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Scope == ScopeProject || (language != "" && rule.Language != language) {
+			continue
+		}
+		selected = append(selected, rule)
+	}
+	if len(selected) == 0 {
+		b.Skipf("no %s rules in resources/rules", language)
+	}
+	return selected
+}
+
+// evalTestcasePerDocument evaluates a rule the way the engine did before
+// rules were compiled once: reading, parsing and compiling everything again
+// for the document.
+func evalTestcasePerDocument(rule Rule, inputFile string) (*Testcase, error) {
+	settings := ruleSettings(rule)
+	switch rule.Language {
+	case LanguageRego:
+		return evalTestcase_Rego(rule.Path, "data."+rule.PackageName, inputFile, rule.RuleNumber, false, benchmarkModelSourcePath, settings)
+	case LanguageJavascript:
+		return evalTestcase_Javascript(rule.Path, inputFile, rule.RuleNumber, false, benchmarkModelSourcePath, settings)
+	case LanguageYaml:
+		return evalTestcase_Yaml(rule.Path, inputFile, rule.RuleNumber, false, benchmarkModelSourcePath, settings)
+	case LanguageJSONSchema:
+		return evalTestcase_Schema(rule.Path, inputFile, rule.RuleNumber, false, benchmarkModelSourcePath)
+	default:
+		return evalTestcase_Typescript(rule.Path, inputFile, rule.RuleNumber, false, benchmarkModelSourcePath, settings)
+	}
+}
+
+// benchmarkPerDocument walks the modelsource for every rule and evaluates
+// each (rule, document) pair from scratch.
+func benchmarkPerDocument(b *testing.B, language string) {
+	rules := benchmarkRules(b, language)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, rule := range rules {
+			inputFiles, err := expandPaths(rule.Pattern, benchmarkModelSourcePath)
+			if err != nil {
+				b.Fatalf("Failed to expand pattern for %s: %v", rule.Path, err)
+			}
+			for _, inputFile := range inputFiles {
+				if _, err := evalTestcasePerDocument(rule, inputFile); err != nil {
+					b.Fatalf("Failed to evaluate %s: %v", rule.Path, err)
+				}
+			}
+		}
+	}
+}
+
+// benchmarkEngine runs the lint engine: one modelsource index per run, each
+// rule compiled once and each document parsed once. It uses a single worker
+// so that it compares with benchmarkPerDocument.
+func benchmarkEngine(b *testing.B, language string) {
+	rules := benchmarkRules(b, language)
+	concurrency := 1
+	SetConfig(&Config{Lint: ConfigLintSpec{Concurrency: &concurrency}})
+	b.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		run, err := newLintRun(benchmarkModelSourcePath, false, false, nil, nil)
+		if err != nil {
+			b.Fatalf("Failed to index modelsource: %v", err)
+		}
+		if _, err := run.evalTestsuites(rules, nil); err != nil {
+			b.Fatalf("Failed to evaluate rules: %v", err)
+		}
+	}
+}
+
+func BenchmarkRegoRules_PerDocument(b *testing.B) {
+	benchmarkPerDocument(b, LanguageRego)
+}
+
+func BenchmarkRegoRules_Engine(b *testing.B) {
+	benchmarkEngine(b, LanguageRego)
+}
+
+func BenchmarkJavascriptRules_PerDocument(b *testing.B) {
+	benchmarkPerDocument(b, LanguageJavascript)
+}
+
+func BenchmarkJavascriptRules_Engine(b *testing.B) {
+	benchmarkEngine(b, LanguageJavascript)
+}
+
+func BenchmarkTypescriptRules_PerDocument(b *testing.B) {
+	benchmarkPerDocument(b, LanguageTypescript)
+}
+
+func BenchmarkTypescriptRules_Engine(b *testing.B) {
+	benchmarkEngine(b, LanguageTypescript)
+}
+
+func BenchmarkAllRules_PerDocument(b *testing.B) {
+	benchmarkPerDocument(b, "")
+}
+
+func BenchmarkAllRules_Engine(b *testing.B) {
+	benchmarkEngine(b, "")
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/sobek"
@@ -53,22 +54,29 @@ func resolvePath(pathArg string, workingDirectory string, allowedRoot string) (s
 
 // readYAMLDocumentFromPath reads a YAML file and decodes it into a map suitable for JavaScript rules.
 func readYAMLDocumentFromPath(absPath string) (map[string]interface{}, error) {
+	data, _, err := readYAMLNodeFromPath(absPath)
+	return data, err
+}
+
+// readYAMLNodeFromPath reads a YAML file and returns both the decoded map and
+// the parsed node, which is used to resolve violation locations.
+func readYAMLNodeFromPath(absPath string) (map[string]interface{}, *yaml.Node, error) {
 	documentContent, err := os.ReadFile(absPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var data map[string]interface{}
 	var node yaml.Node
 	err = yaml.Unmarshal(documentContent, &node)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	err = node.Decode(&data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return data, nil
+	return data, &node, nil
 }
 
 // readJSONDocumentFromPath reads a JSON file and decodes it into a value suitable for JavaScript rules.
@@ -100,11 +108,22 @@ func readJSONDocumentFromPath(absPath string) (interface{}, error) {
 //     The path is resolved relative to the workingDirectory.
 func setupJavascriptVM(workingDirectory string, allowedRoot string) *sobek.Runtime {
 	vm := sobek.New()
+	bindMxlint(vm, workingDirectory, allowedRoot)
+	return vm
+}
 
+// bindMxlint sets a fresh mxlint object on vm, replacing any previous one, so
+// that a pooled runtime resolves paths for the document being evaluated and
+// sees a new copy of mxlint.data.
+func bindMxlint(vm *sobek.Runtime, workingDirectory string, allowedRoot string) {
 	// Create the mxlint object
 	mxlint := vm.NewObject()
 	vm.Set("mxlint", mxlint)
 
+	// Expose a copy of the external data files, so rules cannot change the
+	// data seen by other documents.
+	mxlint.Set("data", vm.ToValue(cloneDocumentValue(getRulesData())))
+
 	// Create the io sub-object
 	io := vm.NewObject()
 	mxlint.Set("io", io)
@@ -214,22 +233,117 @@ func setupJavascriptVM(workingDirectory string, allowedRoot string) *sobek.Runti
 
 		return vm.ToValue(info.IsDir())
 	})
+}
 
-	return vm
+// compiledJavascriptRule is a JavaScript or TypeScript rule compiled once
+// per run. Runtimes that have loaded the program are pooled and reused across
+// documents. The program is a factory that runs the top-level rule code in a
+// fresh scope for every document, globals added by the rule are removed
+// before a runtime is reused, and the document, settings and mxlint.data are
+// copied for every evaluation, so documents cannot see each other's state.
+// Changes a rule makes to built-in objects and prototypes are not undone.
+type compiledJavascriptRule struct {
+	path     string
+	program  *sobek.Program
+	settings map[string]interface{}
+	runtimes sync.Pool
 }
 
-func evalTestcase_Javascript(rulePath string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
-	ruleContent, _ := os.ReadFile(rulePath)
+type javascriptRuleRuntime struct {
+	vm      *sobek.Runtime
+	factory sobek.Callable
+	// globals are the enumerable globals of a new runtime.
+	globals map[string]bool
+}
+
+func compileJavascriptRule(rulePath string, source string, settings map[string]interface{}) (*compiledJavascriptRule, error) {
+	// The wrapper starts on the first line of the rule so that error
+	// positions still match the rule source.
+	factory := "(function () {" + source + "\nreturn typeof rule === \"undefined\" ? undefined : rule;\n})"
+	program, err := sobek.Compile(rulePath, factory, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile rule %s: %w", rulePath, err)
+	}
+	return &compiledJavascriptRule{path: rulePath, program: program, settings: settings}, nil
+}
+
+// prepareJavascriptRule bundles and compiles a JavaScript rule.
+func prepareJavascriptRule(rulePath string, settings map[string]interface{}) (*compiledJavascriptRule, error) {
+	ruleContent, err := bundleJavascriptRule(rulePath)
+	if err != nil {
+		return nil, err
+	}
 	log.Debugf("js file: \n%s", ruleContent)
+	return compileJavascriptRule(rulePath, ruleContent, settings)
+}
+
+// acquire returns a pooled runtime, or a new one that still has to be
+// loaded, with mxlint bound to the given directories.
+func (c *compiledJavascriptRule) acquire(workingDirectory string, allowedRoot string) *javascriptRuleRuntime {
+	if runtime, ok := c.runtimes.Get().(*javascriptRuleRuntime); ok {
+		bindMxlint(runtime.vm, workingDirectory, allowedRoot)
+		return runtime
+	}
+
+	vm := sobek.New()
+	bindMxlint(vm, workingDirectory, allowedRoot)
+	globals := map[string]bool{}
+	for _, key := range vm.GlobalObject().Keys() {
+		globals[key] = true
+	}
+	return &javascriptRuleRuntime{vm: vm, globals: globals}
+}
 
-	data, err := readYAMLDocumentFromPath(inputFilePath)
+// load runs the top-level rule code in a fresh scope and returns its rule
+// function. The bindings are set first so top-level rule code can use them.
+func (c *compiledJavascriptRule) load(runtime *javascriptRuleRuntime) (sobek.Callable, error) {
+	if runtime.factory == nil {
+		value, err := runtime.vm.RunProgram(c.program)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rule %s: %w", c.path, err)
+		}
+		factory, ok := sobek.AssertFunction(value)
+		if !ok {
+			return nil, fmt.Errorf("failed to load rule %s", c.path)
+		}
+		runtime.factory = factory
+	}
+	value, err := runtime.factory(sobek.Undefined())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rule %s: %w", c.path, err)
+	}
+	ruleFunction, ok := sobek.AssertFunction(value)
+	if !ok {
+		return nil, fmt.Errorf("rule(...) function not found in rule file: %s", c.path)
+	}
+	return ruleFunction, nil
+}
+
+// release returns a runtime to the pool after removing the globals the rule
+// added. Runtimes of evaluations that failed are dropped.
+func (c *compiledJavascriptRule) release(runtime *javascriptRuleRuntime) {
+	global := runtime.vm.GlobalObject()
+	for _, key := range global.Keys() {
+		if !runtime.globals[key] {
+			if err := global.Delete(key); err != nil {
+				return
+			}
+		}
+	}
+	c.runtimes.Put(runtime)
+}
+
+func (c *compiledJavascriptRule) evalDocument(document *modelDocument, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	rulePath := c.path
+	inputFilePath := document.path
+	data, node, err := document.yamlDocument()
 	if err != nil {
 		log.Errorf("Error reading YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
 		return nil, err
 	}
 
 	// Check if this rule should be skipped based on noqa directives
-	doc, _ := data["Documentation"].(string)
+	doc, _ := data[documentationKey].(string)
 	shouldSkip, reason := shouldSkipRule(doc, ruleNumber, ignoreNoqa, inputFilePath, modelSourcePath)
 	if shouldSkip {
 		return &Testcase{
@@ -247,78 +361,94 @@ func evalTestcase_Javascript(rulePath string, inputFilePath string, ruleNumber s
 		workingDirectory = filepath.Dir(inputFilePath)
 	}
 	allowedRoot := resolveAllowedRoot(modelSourcePath)
-	vm := setupJavascriptVM(workingDirectory, allowedRoot)
-	_, err = vm.RunString(string(ruleContent))
-	if err != nil {
-		panic(err)
+	runtime := c.acquire(workingDirectory, allowedRoot)
+	vm := runtime.vm
+
+	stop := interruptAfter(vm, ruleTimeout())
+	var res sobek.Value
+	ruleFunction, loadErr := c.load(runtime)
+	if loadErr == nil {
+		res, err = ruleFunction(sobek.Undefined(), vm.ToValue(cloneDocumentValue(data)), vm.ToValue(cloneDocumentValue(c.settings)))
 	}
-
-	ruleFunction, ok := sobek.AssertFunction(vm.Get("rule"))
-	if !ok {
-		panic("rule(...) function not found in rule file: " + rulePath)
+	if !stop() && loadErr == nil && err == nil {
+		c.release(runtime)
 	}
-
-	res, err := ruleFunction(sobek.Undefined(), vm.ToValue(data))
-
 	duration := time.Since(startTime)
-	var failure *Failure = nil
-
+	if isRuleTimeout(loadErr) || isRuleTimeout(err) {
+		return newTimeoutTestcase(rulePath, inputFilePath, duration), nil
+	}
+	if loadErr != nil {
+		err = newRuleEvalError(ruleErrorLoad, loadErr)
+	}
+	var result bool
+	var errors []interface{}
+	if err == nil {
+		log.Debugf("Result: %v", res.Export())
+		result, errors, err = parseRuleResult(res.Export())
+	}
 	if err != nil {
-		errorMessage := fmt.Sprintf("Error evaluating javascript rule %v for inputfile %v: %v", rulePath, inputFilePath, err)
-		log.Error(errorMessage)
-
-		failure = &Failure{
-			Message: errorMessage,
-			Type:    "RuntimeError",
-		}
-
-		testcase := &Testcase{
-			Name:    inputFilePath,
-			Time:    float64(duration.Nanoseconds()) / 1e9, // convert to seconds
-			Failure: failure,
-			Skipped: nil,
-		}
+		testcase := newRuleErrorTestcase(rulePath, inputFilePath, err, duration)
+		log.Error(testcase.Error.Message)
 		return testcase, nil
 	}
 
-	rs := res.Export().(map[string]interface{})
+	return newRuleResultTestcase(inputFilePath, data, node, ruleNumber, ignoreNoqa, result, errors, duration), nil
+}
 
-	log.Debugf("Result: %v", rs)
-	result := rs["allow"].(bool)
-	errors := rs["errors"].([]interface{})
-	if !result {
-		myErrors := make([]string, 0)
-		for _, err := range errors {
-			//log.Warnf("Rule failed: %s", err)
-			myErrors = append(myErrors, fmt.Sprintf("%s", err))
-		}
-		failure = &Failure{
-			Message: strings.Join(myErrors, "\n"),
-			Type:    "AssertionError",
-		}
+// evalTestCase runs the rule on the input of a test case. mxlint.io
+// resolves paths against the directory of the rule.
+func (c *compiledJavascriptRule) evalTestCase(input map[string]interface{}) (bool, []interface{}, error) {
+	workingDirectory := filepath.Dir(c.path)
+	runtime := c.acquire(workingDirectory, workingDirectory)
+
+	stop := interruptAfter(runtime.vm, ruleTimeout())
+	var res sobek.Value
+	var err error
+	ruleFunction, loadErr := c.load(runtime)
+	if loadErr == nil {
+		res, err = ruleFunction(sobek.Undefined(), runtime.vm.ToValue(input), runtime.vm.ToValue(cloneDocumentValue(c.settings)))
 	}
-	testcase := &Testcase{
-		Name:    inputFilePath,
-		Time:    float64(duration.Nanoseconds()) / 1e9, // convert to seconds
-		Failure: failure,
-		Skipped: nil,
+	if !stop() && loadErr == nil && err == nil {
+		c.release(runtime)
+	}
+	if isRuleTimeout(loadErr) || isRuleTimeout(err) {
+		return false, nil, fmt.Errorf("rule %s timed out after %s: %w", c.path, ruleTimeout(), errRuleTimeout)
+	}
+	if loadErr != nil {
+		return false, nil, newRuleEvalError(ruleErrorLoad, loadErr)
+	}
+	if err != nil {
+		return false, nil, err
 	}
-	return testcase, nil
+	return parseRuleResult(res.Export())
+}
+
+// exportSettings converts the custom.settings metadata object of a JavaScript
+// or TypeScript rule into a map.
+func exportSettings(value sobek.Value) (map[string]interface{}, error) {
+	if value == nil || sobek.IsUndefined(value) || sobek.IsNull(value) {
+		return nil, nil
+	}
+	settings, ok := value.Export().(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("metadata.custom.settings must be an object")
+	}
+	return settings, nil
 }
 
 func parseRuleMetadata_Javascript(rulePath string) (*Rule, error) {
 
 	log.Debugf("reading rule %s", rulePath)
 
-	// read the rule file
-	ruleContent, err := os.ReadFile(rulePath)
+	// read the rule file with its imports
+	ruleContent, err := bundleJavascriptRule(rulePath)
 	if err != nil {
 		return nil, err
 	}
 
 	// use sobek to extract the metadata from the rule
 	vm := sobek.New()
-	_, err = vm.RunString(string(ruleContent))
+	_, err = vm.RunString(ruleContent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to evaluate javascript rule: %w", err)
 	}
@@ -361,6 +491,11 @@ func parseRuleMetadata_Javascript(rulePath string) (*Rule, error) {
 	var remediation string = getString(custom, "remediation")
 	var ruleName string = getString(custom, "rulename")
 	var pattern string = getString(custom, "input")
+	var scope string = getString(custom, "scope")
+	settings, err := exportSettings(custom.Get("settings"))
+	if err != nil {
+		return nil, err
+	}
 	if strings.TrimSpace(ruleNumber) == "" {
 		return nil, fmt.Errorf("metadata.custom.rulenumber is required")
 	}
@@ -377,6 +512,8 @@ func parseRuleMetadata_Javascript(rulePath string) (*Rule, error) {
 		Pattern:     pattern,
 		PackageName: packageName,
 		Language:    LanguageJavascript,
+		Scope:       normalizeRuleScope(scope, rulePath),
+		Settings:    settings,
 	}
 	return rule, nil
 }
@@ -1,11 +1,26 @@
 package lint
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 )
 
+// evalTestcase_Javascript evaluates a JavaScript rule on a single document.
+func evalTestcase_Javascript(rulePath string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string, settings map[string]interface{}) (*Testcase, error) {
+	compiled, err := prepareJavascriptRule(rulePath, settings)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.evalTestcase(inputFilePath, ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
+func (c *compiledJavascriptRule) evalTestcase(inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	return c.evalDocument(newModelDocument(inputFilePath, ""), ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
 // jsPath formats a filesystem path for embedding in a JavaScript string literal.
 // On Windows, backslashes would otherwise be interpreted as JS escape sequences.
 func jsPath(path string) string {
@@ -834,3 +849,204 @@ func TestMxlintObjectAvailable(t *testing.T) {
 		t.Errorf("Expected mxlint.io.isdir to be a function, got %q", result.String())
 	}
 }
+
+func TestCompiledJavascriptRule_RebindsMxlintPerDocument(t *testing.T) {
+	tempDir := t.TempDir()
+	ruleContent := `
+function rule(input) {
+    const expected = mxlint.io.readfile("expected.txt").trim();
+    const errors = [];
+    if (input.Name !== expected) {
+        errors.push("expected " + expected + " but got " + input.Name);
+    }
+    return { allow: errors.length === 0, errors: errors };
+}
+`
+	compiled, err := compileJavascriptRule("reuse.js", ruleContent, nil)
+	if err != nil {
+		t.Fatalf("Failed to compile rule: %v", err)
+	}
+
+	for _, doc := range []struct {
+		dir  string
+		name string
+	}{
+		{dir: "a", name: "Alpha"},
+		{dir: "b", name: "Beta"},
+	} {
+		dir := filepath.Join(tempDir, doc.dir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := writeTestFile(dir, "expected.txt", doc.name); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if err := writeTestFile(dir, "Doc.yaml", "Name: "+doc.name+"\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	// Without a modelsource path each document resolves files from its own
+	// directory, so the bindings must follow the document on a reused runtime.
+	for _, dir := range []string{"a", "b", "a"} {
+		testcase, err := compiled.evalTestcase(filepath.Join(tempDir, dir, "Doc.yaml"), "001_0001", false, "")
+		if err != nil {
+			t.Fatalf("Failed to evaluate %s: %v", dir, err)
+		}
+		if testcase.Failure != nil {
+			t.Fatalf("expected %s to pass, got: %s", dir, testcase.Failure.Message)
+		}
+	}
+}
+
+func TestCompiledJavascriptRule_IsolatesDocuments(t *testing.T) {
+	ruleContent := `
+let seen = [];
+var count = 0;
+
+function rule(input) {
+    seen.push(input.Name);
+    count++;
+    globalThis.leaked = (globalThis.leaked || 0) + 1;
+    implicit = (typeof implicit === "undefined" ? 0 : implicit) + 1;
+    const errors = [];
+    if (seen.length !== 1 || count !== 1 || globalThis.leaked !== 1 || implicit !== 1) {
+        errors.push("state of a previous document: " + seen.join(","));
+    }
+    if (input.Name === "Throw") {
+        throw new Error("failed");
+    }
+    return { allow: errors.length === 0, errors: errors };
+}
+`
+	compiled, err := compileJavascriptRule("state.js", ruleContent, nil)
+	if err != nil {
+		t.Fatalf("Failed to compile rule: %v", err)
+	}
+	tempDir := t.TempDir()
+	for _, name := range []string{"Alpha", "Beta", "Throw"} {
+		if err := writeTestFile(tempDir, name+".yaml", "Name: "+name+"\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	for _, name := range []string{"Alpha", "Beta", "Throw", "Alpha"} {
+		testcase, err := compiled.evalTestcase(filepath.Join(tempDir, name+".yaml"), "001_0001", false, tempDir)
+		if err != nil {
+			t.Fatalf("Failed to evaluate %s: %v", name, err)
+		}
+		if testcase.Failure != nil {
+			t.Fatalf("expected %s not to see other documents, got: %s", name, testcase.Failure.Message)
+		}
+		if (testcase.Error != nil) != (name == "Throw") {
+			t.Fatalf("unexpected result for %s: %+v", name, testcase)
+		}
+	}
+}
+
+func TestCompiledJavascriptRule_CopiesSettingsPerDocument(t *testing.T) {
+	ruleContent := `
+function rule(input, settings) {
+    settings.seen = (settings.seen || 0) + 1;
+    settings.limits.max = settings.limits.max + 1;
+    const errors = [];
+    if (settings.seen !== 1 || settings.limits.max !== 11) {
+        errors.push("settings of a previous document: " + settings.seen + ", " + settings.limits.max);
+    }
+    return { allow: errors.length === 0, errors: errors };
+}
+`
+	settings := map[string]interface{}{"limits": map[string]interface{}{"max": 10}}
+	compiled, err := compileJavascriptRule("settings.js", ruleContent, settings)
+	if err != nil {
+		t.Fatalf("Failed to compile rule: %v", err)
+	}
+	tempDir := t.TempDir()
+	for _, name := range []string{"Alpha", "Beta"} {
+		if err := writeTestFile(tempDir, name+".yaml", "Name: "+name+"\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	for _, name := range []string{"Alpha", "Beta", "Alpha"} {
+		testcase, err := compiled.evalTestcase(filepath.Join(tempDir, name+".yaml"), "001_0001", false, tempDir)
+		if err != nil {
+			t.Fatalf("Failed to evaluate %s: %v", name, err)
+		}
+		if testcase.Failure != nil {
+			t.Fatalf("expected %s to get its own settings, got: %s", name, testcase.Failure.Message)
+		}
+	}
+	if _, ok := settings["seen"]; ok || settings["limits"].(map[string]interface{})["max"] != 10 {
+		t.Fatalf("expected the configured settings to stay unchanged, got %v", settings)
+	}
+}
+
+func TestCompiledJavascriptRule_CompileError(t *testing.T) {
+	if _, err := compileJavascriptRule("broken.js", "function rule(input) {", nil); err == nil {
+		t.Fatal("expected compile error for invalid rule")
+	}
+
+	compiled, err := compileJavascriptRule("norule.js", "const metadata = {};", nil)
+	if err != nil {
+		t.Fatalf("Failed to compile rule: %v", err)
+	}
+	tempDir := t.TempDir()
+	if err := writeTestFile(tempDir, "Doc.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	testcase, err := compiled.evalTestcase(filepath.Join(tempDir, "Doc.yaml"), "001_0001", false, tempDir)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testcase.Error == nil || testcase.Error.Type != ruleErrorLoad {
+		t.Fatalf("expected a LoadError when rule(...) is missing, got %+v", testcase)
+	}
+}
+
+func TestEvalTestsuites_JavascriptSettingsPerDocument(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	modelDir := t.TempDir()
+	for i := 0; i < 50; i++ {
+		if err := writeTestFile(modelDir, fmt.Sprintf("Doc%02d.yaml", i), "Name: Test\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+	rule := Rule{
+		Path:       writeTestRule(t, "settings.js", "function rule(input, settings) { settings.seen = (settings.seen || 0) + 1; settings.limit = 99; const errors = settings.seen === 1 && settings.limit === 99 ? [] : [\"settings of a previous document\"]; return { allow: errors.length === 0, errors: errors }; }"),
+		RuleNumber: "001_0001",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJavascript,
+		Settings:   map[string]interface{}{"limit": 1},
+	}
+
+	concurrency := 8
+	SetConfig(&Config{Lint: ConfigLintSpec{Concurrency: &concurrency}})
+	run, err := newLintRun(modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to index modelsource: %v", err)
+	}
+	testsuites, err := run.evalTestsuites([]Rule{rule}, nil)
+	if err != nil {
+		t.Fatalf("evalTestsuites returned error: %v", err)
+	}
+	if testsuites[0].Tests != 50 || testsuites[0].Failures != 0 || testsuites[0].Errors != 0 {
+		t.Fatalf("expected every document to get its own settings, got %+v", testsuites[0].Testcases)
+	}
+}
+
+func TestCompiledJavascriptRule_EvalTestCaseTimeout(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{Lint: ConfigLintSpec{RuleTimeout: "50ms"}})
+	compiled, err := compileJavascriptRule("loop.js", "function rule(input) { while (true) {} }", nil)
+	if err != nil {
+		t.Fatalf("Failed to compile rule: %v", err)
+	}
+	if _, _, err := compiled.evalTestCase(map[string]interface{}{}); !errors.Is(err, errRuleTimeout) {
+		t.Fatalf("expected the test case to time out, got: %v", err)
+	}
+}
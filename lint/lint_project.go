@@ -0,0 +1,342 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/topdown"
+	"gopkg.in/yaml.v3"
+)
+
+// ScopeProject marks rules that run once per lint with the whole modelsource
+// as input. Rules without a scope run once per matching document.
+const ScopeProject = "project"
+
+// projectMetadataDocument is the modelsource document that receives project
+// rule errors which do not name any document.
+const projectMetadataDocument = "Metadata.yaml"
+
+// projectInput is the input of a project-scoped rule: every matching
+// document keyed by its modelsource-relative path, plus Metadata.yaml.
+type projectInput struct {
+	Documents map[string]interface{}
+	Metadata  map[string]interface{}
+	// nodes holds the parsed documents used to resolve violation locations.
+	nodes map[string]*yaml.Node
+}
+
+func (p projectInput) toValue() map[string]interface{} {
+	return map[string]interface{}{
+		"documents": p.Documents,
+		"metadata":  p.Metadata,
+	}
+}
+
+// document returns the parsed document with the given name, or nil.
+func (p projectInput) document(name string) map[string]interface{} {
+	if document, ok := p.Documents[name].(map[string]interface{}); ok {
+		return document
+	}
+	if name == projectMetadataDocument {
+		return p.Metadata
+	}
+	return nil
+}
+
+// normalizeRuleScope returns ScopeProject for project rules and "" for the
+// default per-document scope.
+func normalizeRuleScope(scope string, rulePath string) string {
+	trimmed := strings.TrimSpace(scope)
+	if strings.EqualFold(trimmed, ScopeProject) {
+		return ScopeProject
+	}
+	if trimmed != "" && !strings.EqualFold(trimmed, "document") {
+		log.Warnf("Unknown scope %q in rule %s; evaluating per document", trimmed, rulePath)
+	}
+	return ""
+}
+
+// loadProjectInput collects the parsed YAML documents of a project rule and
+// Metadata.yaml, which may be nil. It returns the input and the document names
+// in walk order.
+func loadProjectInput(documents []*modelDocument, metadata *modelDocument, modelSourcePath string) (projectInput, []string, error) {
+	input := projectInput{
+		Documents: make(map[string]interface{}, len(documents)),
+		nodes:     make(map[string]*yaml.Node, len(documents)),
+	}
+	names := make([]string, 0, len(documents))
+	for _, document := range documents {
+		if !strings.HasSuffix(document.path, ".yaml") {
+			continue
+		}
+		data, node, err := document.yamlDocument()
+		if err != nil {
+			return projectInput{}, nil, fmt.Errorf("failed to read %s: %w", document.path, err)
+		}
+		name := formatTestcaseName(document.path, modelSourcePath)
+		input.Documents[name] = data
+		input.nodes[name] = node
+		names = append(names, name)
+	}
+
+	if metadata != nil {
+		data, node, err := metadata.yamlDocument()
+		if err != nil {
+			return projectInput{}, nil, fmt.Errorf("failed to read %s: %w", projectMetadataDocument, err)
+		}
+		input.Metadata = data
+		if input.nodes[projectMetadataDocument] == nil {
+			input.nodes[projectMetadataDocument] = node
+		}
+	}
+
+	return input, names, nil
+}
+
+// evalProjectTestsuite evaluates a project-scoped rule once and attributes
+// each error to the documents it names. Results are not cached because the
+// input spans the whole modelsource.
+func evalProjectTestsuite(rule Rule, run *lintRun, documents []*modelDocument, metadata *modelDocument) (*Testsuite, error) {
+	modelSourcePath := run.modelSourcePath
+	input, names, err := loadProjectInput(documents, metadata, modelSourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+	allow, errors, evalErr := evalProjectRule(rule, input.toValue(), modelSourcePath)
+	duration := time.Since(startTime)
+
+	attributed := map[string][]Violation{}
+	suppressed := map[string][]string{}
+	timedOut := isRuleTimeout(evalErr)
+	var ruleError *RuleError
+	if timedOut {
+		message := fmt.Sprintf("Rule %s timed out after %s on %s", rule.Path, ruleTimeout(), projectMetadataDocument)
+		log.Error(message)
+		attributed[projectMetadataDocument] = []Violation{{Message: message}}
+	} else if evalErr != nil {
+		ruleError = newRuleError(rule.Path, projectMetadataDocument, evalErr)
+		log.Error(ruleError.Message)
+		attributed[projectMetadataDocument] = nil
+	} else if !allow {
+		attributed = attributeProjectErrors(parseViolations(errors), names)
+		for name, violations := range attributed {
+			attributed[name], suppressed[name] = suppressNoqaViolations(input.document(name), violations, rule.RuleNumber, run.ignoreNoqa)
+			resolveViolationLocations(input.nodes[name], attributed[name])
+		}
+	}
+
+	// Violations may name documents outside the rule input, such as Metadata.yaml.
+	extra := make([]string, 0)
+	for name := range attributed {
+		if _, ok := input.Documents[name]; !ok {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	names = append(names, extra...)
+
+	changedSet := normalizeChangedFilesSet(run.changedFiles)
+	testcases := make([]Testcase, 0, len(names))
+	failuresCount := 0
+	errorsCount := 0
+	skippedCount := 0
+	for _, name := range names {
+		documentPath := filepath.Join(modelSourcePath, filepath.FromSlash(name))
+		if changedSet != nil {
+			if _, changed := changedSet[cleanPath(documentPath)]; !changed {
+				continue
+			}
+		}
+
+		testcase := Testcase{
+			Name:         name,
+			OriginalPath: resolveOriginalPath(name, run.originalPathMap),
+		}
+
+		doc, _ := input.document(name)[documentationKey].(string)
+		if shouldSkip, reason := shouldSkipRule(doc, rule.RuleNumber, run.ignoreNoqa, documentPath, modelSourcePath); shouldSkip {
+			testcase.Skipped = &Skipped{Message: reason}
+			skippedCount++
+		} else if ruleError != nil && name == projectMetadataDocument {
+			testcase.Error = ruleError
+			errorsCount++
+		} else if violations := attributed[name]; len(violations) > 0 {
+			testcase.Failure = newAssertionFailure(violations)
+			testcase.Violations = violations
+			if timedOut {
+				testcase.Failure.Type = failureTypeTimeout
+				testcase.Violations = nil
+			}
+			failuresCount++
+		} else if reasons := suppressed[name]; len(reasons) > 0 {
+			testcase.Skipped = &Skipped{Message: strings.Join(reasons, "; ")}
+			skippedCount++
+		}
+		testcases = append(testcases, testcase)
+	}
+
+	return &Testsuite{
+		Name:      rule.Path,
+		Tests:     len(testcases),
+		Failures:  failuresCount,
+		Errors:    errorsCount,
+		Skipped:   skippedCount,
+		Time:      float64(duration.Nanoseconds()) / 1e9, // convert to seconds
+		Testcases: testcases,
+	}, nil
+}
+
+// attributeProjectErrors assigns each violation to the document it sets, or
+// else to every document whose path, with or without the .yaml extension,
+// appears in the message. Violations that name no document are attributed to
+// Metadata.yaml.
+func attributeProjectErrors(violations []Violation, names []string) map[string][]Violation {
+	// Match longer names first and claim the matched text so that
+	// "Mod/DocA.yaml" is not also attributed to a sibling "Mod/Doc.yaml".
+	candidates := append([]string{}, names...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i]) > len(candidates[j])
+	})
+
+	known := make(map[string]bool, len(names))
+	for _, name := range names {
+		known[name] = true
+	}
+
+	attributed := map[string][]Violation{}
+	for _, violation := range violations {
+		if document := strings.TrimPrefix(filepath.ToSlash(violation.Document), "/"); document != "" {
+			if !known[document] && known[document+".yaml"] {
+				document += ".yaml"
+			}
+			attributed[document] = append(attributed[document], violation)
+			continue
+		}
+
+		message := violation.Message
+		claimed := make([]bool, len(message))
+		matched := false
+		for _, name := range candidates {
+			if claimProjectDocumentName(message, name, claimed) || claimProjectDocumentName(message, strings.TrimSuffix(name, ".yaml"), claimed) {
+				attributed[name] = append(attributed[name], violation)
+				matched = true
+			}
+		}
+		if !matched {
+			attributed[projectMetadataDocument] = append(attributed[projectMetadataDocument], violation)
+		}
+	}
+	return attributed
+}
+
+// claimProjectDocumentName marks the first unclaimed occurrence of name in
+// message as claimed and reports whether one was found.
+func claimProjectDocumentName(message string, name string, claimed []bool) bool {
+	if name == "" {
+		return false
+	}
+	for offset := 0; offset+len(name) <= len(message); {
+		index := strings.Index(message[offset:], name)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(name)
+		if !slices.Contains(claimed[start:end], true) {
+			for i := start; i < end; i++ {
+				claimed[i] = true
+			}
+			return true
+		}
+		offset = start + 1
+	}
+	return false
+}
+
+func evalProjectRule(rule Rule, input map[string]interface{}, modelSourcePath string) (bool, []interface{}, error) {
+	switch rule.Language {
+	case LanguageRego:
+		return evalProjectRule_Rego(rule, input)
+	case LanguageJavascript:
+		ruleContent, err := bundleJavascriptRule(rule.Path)
+		if err != nil {
+			return false, nil, newRuleEvalError(ruleErrorLoad, err)
+		}
+		return evalProjectRule_Javascript(rule, ruleContent, input, modelSourcePath)
+	case LanguageTypescript:
+		ruleContent, err := transpileTypescriptRule(rule.Path)
+		if err != nil {
+			return false, nil, newRuleEvalError(ruleErrorLoad, err)
+		}
+		return evalProjectRule_Javascript(rule, ruleContent, input, modelSourcePath)
+	}
+	return false, nil, newRuleEvalError(ruleErrorLoad, fmt.Errorf("project scope is not supported for %s rules", rule.Language))
+}
+
+func evalProjectRule_Rego(rule Rule, input map[string]interface{}) (bool, []interface{}, error) {
+	regoFile, err := os.ReadFile(rule.Path)
+	if err != nil {
+		return false, nil, newRuleEvalError(ruleErrorLoad, err)
+	}
+	regoContent := quoteRegoMetadataRulenumber(string(regoFile))
+
+	query, err := rego.New(
+		rego.Query("data."+rule.PackageName),
+		rego.Module(rule.Path, regoContent),
+		rego.Store(regoDataStore(ruleSettings(rule))),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return false, nil, newRuleEvalError(ruleErrorLoad, err)
+	}
+
+	evalOptions := []rego.EvalOption{rego.EvalInput(input)}
+	if regoTraceEnabled() {
+		evalOptions = append(evalOptions, rego.EvalQueryTracer(topdown.NewBufferTracer()))
+	}
+	ctx := context.Background()
+	if timeout := ruleTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	rs, err := query.Eval(ctx, evalOptions...)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return false, nil, fmt.Errorf("rule %s timed out after %s: %w", rule.Path, ruleTimeout(), errRuleTimeout)
+		}
+		return false, nil, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("query data.%s returned no result", rule.PackageName))
+	}
+	return parseRuleResult(rs[0].Expressions[0].Value)
+}
+
+func evalProjectRule_Javascript(rule Rule, ruleContent string, input map[string]interface{}, modelSourcePath string) (bool, []interface{}, error) {
+	vm := setupJavascriptVM(modelSourcePath, resolveAllowedRoot(modelSourcePath))
+	stop := interruptAfter(vm, ruleTimeout())
+	defer stop()
+	if _, err := vm.RunString(ruleContent); err != nil {
+		return false, nil, newRuleEvalError(ruleErrorLoad, err)
+	}
+	ruleFunction, ok := sobek.AssertFunction(vm.Get("rule"))
+	if !ok {
+		return false, nil, newRuleEvalError(ruleErrorLoad, fmt.Errorf("rule(...) function not found in rule file: %s", rule.Path))
+	}
+	res, err := ruleFunction(sobek.Undefined(), vm.ToValue(cloneDocumentValue(input)), vm.ToValue(ruleSettings(rule)))
+	if err != nil {
+		return false, nil, err
+	}
+	return parseRuleResult(res.Export())
+}
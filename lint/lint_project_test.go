@@ -0,0 +1,193 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeProjectModelsource(t *testing.T) string {
+	t.Helper()
+	modelDir := t.TempDir()
+	files := map[string]string{
+		"Metadata.yaml": "ProductVersion: 10.0.0\nModules:\n- Name: MyFirstModule\n- Name: Other\n",
+		"MyFirstModule/Security$ModuleSecurity.yaml": "ModuleRoles:\n- Name: User\n",
+		"Other/Security$ModuleSecurity.yaml":         "ModuleRoles: []\n",
+		"Other/DomainModels$DomainModel.yaml":        "Documentation: \"\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(modelDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+	return modelDir
+}
+
+const projectRegoRule = `# METADATA
+# scope: package
+# title: Every module has a module role
+# description: Modules without module roles cannot be secured
+# custom:
+#  category: Security
+#  rulename: ModuleRoles
+#  severity: HIGH
+#  rulenumber: 099_0010
+#  remediation: Add a module role
+#  input: .*Security\$ModuleSecurity\.yaml
+#  scope: project
+package test.project_module_roles
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains error if {
+    some path, doc in input.documents
+    count(doc.ModuleRoles) == 0
+    error := sprintf("%v has no module roles", [path])
+}
+
+errors contains error if {
+    count(input.metadata.Modules) > 1
+    error := "project has more than one module"
+}
+`
+
+const projectJavascriptRule = `
+const metadata = {
+    title: "Document count",
+    description: "Counts documents",
+    custom: {
+        category: "Test",
+        rulename: "DocumentCount",
+        severity: "LOW",
+        rulenumber: "099_0011",
+        remediation: "None",
+        input: "",
+        scope: "project"
+    }
+};
+
+function rule(input) {
+    const errors = [];
+    const names = Object.keys(input.documents).sort();
+    errors.push("found " + names.length + " documents, first " + names[0]);
+    if (input.metadata.ProductVersion !== "10.0.0") {
+        errors.push("unexpected product version");
+    }
+    return { allow: false, errors: errors };
+}
+`
+
+func TestEvalProjectTestsuite_Rego(t *testing.T) {
+	rulesDir := t.TempDir()
+	modelDir := writeProjectModelsource(t)
+	rulePath := filepath.Join(rulesDir, "099_0010_module_roles.rego")
+	if err := os.WriteFile(rulePath, []byte(projectRegoRule), 0644); err != nil {
+		t.Fatalf("Failed to write rego file: %v", err)
+	}
+
+	rule, err := parseRuleMetadata_Rego(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+	if rule.Scope != ScopeProject {
+		t.Fatalf("expected project scope, got %q", rule.Scope)
+	}
+
+	testsuite, err := evalTestsuite(*rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate project rule: %v", err)
+	}
+
+	results := map[string]Testcase{}
+	for _, tc := range testsuite.Testcases {
+		results[tc.Name] = tc
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected two module security documents plus Metadata.yaml, got %+v", testsuite.Testcases)
+	}
+	if tc := results["MyFirstModule/Security$ModuleSecurity.yaml"]; tc.Failure != nil {
+		t.Fatalf("expected module with roles to pass, got %+v", tc.Failure)
+	}
+	if tc := results["Other/Security$ModuleSecurity.yaml"]; tc.Failure == nil || tc.Failure.Message != "Other/Security$ModuleSecurity.yaml has no module roles" {
+		t.Fatalf("expected failure attributed to Other module, got %+v", tc.Failure)
+	}
+	if tc := results["Metadata.yaml"]; tc.Failure == nil || tc.Failure.Message != "project has more than one module" {
+		t.Fatalf("expected unattributed error on Metadata.yaml, got %+v", tc.Failure)
+	}
+	if testsuite.Failures != 2 {
+		t.Fatalf("expected 2 failures, got %d", testsuite.Failures)
+	}
+}
+
+func TestEvalProjectTestsuite_Javascript(t *testing.T) {
+	rulesDir := t.TempDir()
+	modelDir := writeProjectModelsource(t)
+	rulePath := filepath.Join(rulesDir, "099_0011_document_count.js")
+	if err := os.WriteFile(rulePath, []byte(projectJavascriptRule), 0644); err != nil {
+		t.Fatalf("Failed to write js file: %v", err)
+	}
+
+	rule, err := parseRuleMetadata_Javascript(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+
+	testsuite, err := evalTestsuite(*rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate project rule: %v", err)
+	}
+	if testsuite.Tests != 4 || testsuite.Failures != 1 {
+		t.Fatalf("expected 4 documents with 1 failure, got %d tests and %d failures", testsuite.Tests, testsuite.Failures)
+	}
+	for _, tc := range testsuite.Testcases {
+		if tc.Name == "Metadata.yaml" {
+			if tc.Failure == nil || tc.Failure.Message != "found 4 documents, first Metadata.yaml" {
+				t.Fatalf("unexpected Metadata.yaml failure: %+v", tc.Failure)
+			}
+		} else if tc.Failure != nil {
+			t.Fatalf("expected %s to pass, got %+v", tc.Name, tc.Failure)
+		}
+	}
+
+	t.Run("diff mode only reports changed documents", func(t *testing.T) {
+		changed := []string{filepath.Join(modelDir, "Other", "DomainModels$DomainModel.yaml")}
+		testsuite, err := evalTestsuite(*rule, modelDir, false, false, changed, nil)
+		if err != nil {
+			t.Fatalf("Failed to evaluate project rule: %v", err)
+		}
+		if testsuite.Tests != 1 || testsuite.Testcases[0].Name != "Other/DomainModels$DomainModel.yaml" {
+			t.Fatalf("expected only the changed document, got %+v", testsuite.Testcases)
+		}
+	})
+}
+
+func TestAttributeProjectErrors(t *testing.T) {
+	names := []string{"Mod/Doc.yaml", "Mod/DocA.yaml", "Other/Doc.yaml"}
+	violations := parseViolations([]interface{}{
+		"Mod/DocA.yaml is broken",
+		"Mod/Doc and Other/Doc share a name",
+		"nothing named",
+		map[string]interface{}{"message": "explicit", "document": "Other/Doc"},
+	})
+
+	attributed := attributeProjectErrors(violations, names)
+
+	if got := attributed["Mod/DocA.yaml"]; len(got) != 1 || got[0].Message != "Mod/DocA.yaml is broken" {
+		t.Fatalf("unexpected Mod/DocA.yaml attribution: %v", got)
+	}
+	if got := attributed["Mod/Doc.yaml"]; len(got) != 1 || got[0].Message != "Mod/Doc and Other/Doc share a name" {
+		t.Fatalf("expected prefix of a longer name not to be attributed, got %v", got)
+	}
+	if got := attributed["Other/Doc.yaml"]; len(got) != 2 || got[1].Message != "explicit" {
+		t.Fatalf("expected named and explicit document attribution, got %v", got)
+	}
+	if got := attributed[projectMetadataDocument]; len(got) != 1 || got[0].Message != "nothing named" {
+		t.Fatalf("expected unattributed error on Metadata.yaml, got %v", got)
+	}
+}
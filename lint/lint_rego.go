@@ -2,43 +2,64 @@ package lint
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/topdown"
 	"gopkg.in/yaml.v3"
 )
 
-func evalTestcase_Rego(rulePath string, queryString string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
-	regoFile, _ := os.ReadFile(rulePath)
+// preparedRegoRule is a Rego rule compiled once and evaluated against many
+// documents. The prepared query is safe for concurrent use.
+type preparedRegoRule struct {
+	path        string
+	queryString string
+	query       rego.PreparedEvalQuery
+}
+
+// prepareRegoRule reads, parses and compiles the rule module with its settings
+// so that only the input changes between documents.
+func prepareRegoRule(rulePath string, queryString string, settings map[string]interface{}) (*preparedRegoRule, error) {
+	regoFile, err := os.ReadFile(rulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule %s: %w", rulePath, err)
+	}
 	log.Debugf("rego file: \n%s", regoFile)
 
 	// Pre-process rego content to quote rulenumber in metadata
 	// This prevents YAML 1.1 octal interpretation of values like "002_0002"
 	regoContent := quoteRegoMetadataRulenumber(string(regoFile))
 
-	yamlFile, err := os.ReadFile(inputFilePath)
+	query, err := rego.New(
+		rego.Query(queryString),
+		rego.Module(rulePath, regoContent),
+		rego.Store(regoDataStore(settings)),
+	).PrepareForEval(context.Background())
 	if err != nil {
-		log.Errorf("Error reading YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
-		return nil, err
+		return nil, fmt.Errorf("failed to compile rule %s: %w", rulePath, err)
 	}
+	return &preparedRegoRule{path: rulePath, queryString: queryString, query: query}, nil
+}
 
-	var data map[string]interface{}
-	var node yaml.Node
-	err = yaml.Unmarshal(yamlFile, &node)
+func (p *preparedRegoRule) evalDocument(document *modelDocument, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	rulePath := p.path
+	inputFilePath := document.path
+	data, node, err := document.yamlDocument()
 	if err != nil {
-		log.Errorf("Error parsing YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
+		log.Errorf("Error reading YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
 		return nil, err
 	}
-	err = node.Decode(&data)
+	input, err := document.regoValue()
 	if err != nil {
-		log.Errorf("Error decoding YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
+		log.Errorf("Error converting YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
 		return nil, err
 	}
 
-	doc, _ := data["Documentation"].(string)
+	doc, _ := data[documentationKey].(string)
 	shouldSkip, reason := shouldSkipRule(doc, ruleNumber, ignoreNoqa, inputFilePath, modelSourcePath)
 	if shouldSkip {
 		return &Testcase{
@@ -49,77 +70,70 @@ func evalTestcase_Rego(rulePath string, queryString string, inputFilePath string
 	}
 
 	ctx := context.Background()
+	if timeout := ruleTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
 	startTime := time.Now()
-	regoOptions := []func(*rego.Rego){
-		rego.Query(queryString),
-		rego.Module(rulePath, regoContent),
-		rego.Input(data),
-	}
+	evalOptions := []rego.EvalOption{rego.EvalParsedInput(input)}
 	if regoTraceEnabled() {
-		regoOptions = append(regoOptions, rego.Trace(true))
+		// One tracer per evaluation; the prepared query is shared across goroutines.
+		evalOptions = append(evalOptions, rego.EvalQueryTracer(topdown.NewBufferTracer()))
 	}
-	r := rego.New(regoOptions...)
 
-	rs, err := r.Eval(ctx)
+	rs, err := p.query.Eval(ctx, evalOptions...)
 	if err != nil {
-		log.Fatal(err)
-		return nil, err
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return newTimeoutTestcase(rulePath, inputFilePath, time.Since(startTime)), nil
+		}
+		testcase := newRuleErrorTestcase(rulePath, inputFilePath, err, time.Since(startTime))
+		log.Error(testcase.Error.Message)
+		return testcase, nil
 	}
 	duration := time.Since(startTime)
 
-	var failure *Failure = nil
-
 	log.Debugf("Result: %v", rs)
-	rsmap := rs[0].Expressions[0].Value.(map[string]interface{})
-	result := rsmap["allow"].(bool)
-	errors, ok := rsmap["errors"].([]interface{})
-
-	if !ok || errors == nil {
-		errors = []interface{}{}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		testcase := newRuleErrorTestcase(rulePath, inputFilePath, newRuleEvalError(ruleErrorContract, fmt.Errorf("query %s returned no result", p.queryString)), duration)
+		log.Error(testcase.Error.Message)
+		return testcase, nil
 	}
-
-	if !result {
-		myErrors := make([]string, 0)
-		for _, err := range errors {
-			//log.Warnf("Rule failed: %s", err)
-			myErrors = append(myErrors, fmt.Sprintf("%s", err))
-		}
-		failure = &Failure{
-			Message: strings.Join(myErrors, "\n"),
-			Type:    "AssertionError",
-		}
-	}
-	testcase := &Testcase{
-		Name:    inputFilePath,
-		Time:    float64(duration.Nanoseconds()) / 1e9, // convert to seconds
-		Failure: failure,
-		Skipped: nil,
+	result, errors, err := parseRuleResult(rs[0].Expressions[0].Value)
+	if err != nil {
+		testcase := newRuleErrorTestcase(rulePath, inputFilePath, err, duration)
+		log.Error(testcase.Error.Message)
+		return testcase, nil
 	}
-	return testcase, nil
-}
 
-func parseRuleMetadata_Rego(rulePath string) (*Rule, error) {
-
-	log.Debugf("reading rule %s", rulePath)
+	return newRuleResultTestcase(inputFilePath, data, node, ruleNumber, ignoreNoqa, result, errors, duration), nil
+}
 
-	// read the rule file
-	ruleContent, err := os.ReadFile(rulePath)
-	if err != nil {
-		return nil, err
-	}
+// regoMetadata is the METADATA annotation of a Rego rule.
+type regoMetadata struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Custom      struct {
+		Category    string                 `yaml:"category"`
+		RuleName    string                 `yaml:"rulename"`
+		Severity    string                 `yaml:"severity"`
+		RuleNumber  string                 `yaml:"rulenumber"`
+		Remediation string                 `yaml:"remediation"`
+		Input       string                 `yaml:"input"`
+		Scope       string                 `yaml:"scope"`
+		Settings    map[string]interface{} `yaml:"settings"`
+	} `yaml:"custom"`
+}
 
+// readRegoMetadata extracts the package name and the METADATA annotation of
+// a Rego rule. The metadata is empty when the rule has no METADATA block; an
+// error is returned with the package name when the block is not valid YAML.
+func readRegoMetadata(ruleContent string) (string, regoMetadata, error) {
 	var packageName string = ""
-	var pattern string = ""
-	var title string = ""
-	var description string = ""
-	var category string = ""
-	var severity string = ""
-	var ruleNumber string = ""
-	var remediation string = ""
-	var ruleName string = ""
+	var metadata regoMetadata
 
-	lines := strings.Split(string(ruleContent), "\n")
+	lines := strings.Split(ruleContent, "\n")
 
 	// extract package name and collect metadata block
 	var metadataLines []string
@@ -154,47 +168,43 @@ func parseRuleMetadata_Rego(rulePath string) (*Rule, error) {
 		yamlContent := strings.Join(metadataLines, "\n")
 		log.Debugf("Parsing metadata YAML:\n%s", yamlContent)
 
-		var metadata struct {
-			Title       string `yaml:"title"`
-			Description string `yaml:"description"`
-			Custom      struct {
-				Category    string `yaml:"category"`
-				RuleName    string `yaml:"rulename"`
-				Severity    string `yaml:"severity"`
-				RuleNumber  string `yaml:"rulenumber"`
-				Remediation string `yaml:"remediation"`
-				Input       string `yaml:"input"`
-			} `yaml:"custom"`
+		if err := yaml.Unmarshal([]byte(yamlContent), &metadata); err != nil {
+			return packageName, regoMetadata{}, err
 		}
+	}
+	return packageName, metadata, nil
+}
 
-		err = yaml.Unmarshal([]byte(yamlContent), &metadata)
-		if err != nil {
-			log.Warnf("Error parsing metadata YAML: %s", err)
-			// continue with empty metadata on parse failure
-		} else {
-			title = metadata.Title
-			description = metadata.Description
-			category = metadata.Custom.Category
-			ruleName = metadata.Custom.RuleName
-			severity = metadata.Custom.Severity
-			ruleNumber = metadata.Custom.RuleNumber
-			remediation = metadata.Custom.Remediation
-			pattern = metadata.Custom.Input
-		}
+func parseRuleMetadata_Rego(rulePath string) (*Rule, error) {
+
+	log.Debugf("reading rule %s", rulePath)
+
+	// read the rule file
+	ruleContent, err := os.ReadFile(rulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	packageName, metadata, err := readRegoMetadata(string(ruleContent))
+	if err != nil {
+		log.Warnf("Error parsing metadata YAML: %s", err)
+		// continue with empty metadata on parse failure
 	}
 
 	rule := &Rule{
-		Title:       title,
-		Description: description,
-		Category:    category,
-		Severity:    severity,
-		RuleNumber:  ruleNumber,
-		Remediation: remediation,
-		RuleName:    ruleName,
+		Title:       metadata.Title,
+		Description: metadata.Description,
+		Category:    metadata.Custom.Category,
+		Severity:    metadata.Custom.Severity,
+		RuleNumber:  metadata.Custom.RuleNumber,
+		Remediation: metadata.Custom.Remediation,
+		RuleName:    metadata.Custom.RuleName,
 		Path:        rulePath,
-		Pattern:     pattern,
+		Pattern:     metadata.Custom.Input,
 		PackageName: packageName,
 		Language:    LanguageRego,
+		Scope:       normalizeRuleScope(metadata.Custom.Scope, rulePath),
+		Settings:    metadata.Custom.Settings,
 	}
 	return rule, nil
 }
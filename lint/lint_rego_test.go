@@ -3,9 +3,24 @@ package lint
 import (
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 )
 
+// evalTestcase_Rego evaluates a Rego rule on a single document.
+func evalTestcase_Rego(rulePath string, queryString string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string, settings map[string]interface{}) (*Testcase, error) {
+	prepared, err := prepareRegoRule(rulePath, queryString, settings)
+	if err != nil {
+		return nil, err
+	}
+	return prepared.evalTestcase(inputFilePath, ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
+func (p *preparedRegoRule) evalTestcase(inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	return p.evalDocument(newModelDocument(inputFilePath, ""), ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
 func TestParseRuleMetadata_Rego(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -201,7 +216,7 @@ errors contains error if {
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.pass", yamlPath, "001_0001", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.pass", yamlPath, "001_0001", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -244,7 +259,7 @@ errors contains error if {
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.fail", yamlPath, "001_0002", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.fail", yamlPath, "001_0002", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -259,7 +274,7 @@ errors contains error if {
 		}
 	})
 
-	t.Run("documentation noqa is ignored", func(t *testing.T) {
+	t.Run("documentation noqa skips the rule", func(t *testing.T) {
 		regoContent := `# METADATA
 # title: Test Rule
 # custom:
@@ -289,20 +304,20 @@ Name: "Test"
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.noqa", yamlPath, "001_0003", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.noqa", yamlPath, "001_0003", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
 
-		if testcase.Skipped != nil {
-			t.Error("Expected testcase not to be skipped")
+		if testcase.Skipped == nil || !strings.Contains(testcase.Skipped.Message, "#noqa:001_0003") {
+			t.Errorf("Expected testcase to be skipped by the noqa directive, got %+v", testcase.Skipped)
 		}
-		if testcase.Failure == nil {
-			t.Error("Expected failure when documentation noqa is ignored")
+		if testcase.Failure != nil {
+			t.Error("Expected no failure for a skipped testcase")
 		}
 	})
 
-	t.Run("ignoreNoqa has no effect on documentation skip", func(t *testing.T) {
+	t.Run("ignoreNoqa disables documentation noqa", func(t *testing.T) {
 		regoContent := `# METADATA
 # title: Test Rule
 # custom:
@@ -332,7 +347,7 @@ Name: "Test"
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.ignore_noqa", yamlPath, "001_0004", true, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.ignore_noqa", yamlPath, "001_0004", true, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -363,7 +378,7 @@ errors contains error if {
 			t.Fatalf("Failed to write rego file: %v", err)
 		}
 
-		_, err = evalTestcase_Rego(regoPath, "data.test.error", filepath.Join(tempDir, "nonexistent.yaml"), "001_0005", false, tempDir)
+		_, err = evalTestcase_Rego(regoPath, "data.test.error", filepath.Join(tempDir, "nonexistent.yaml"), "001_0005", false, tempDir, nil)
 		if err == nil {
 			t.Error("Expected error for nonexistent input file")
 		}
@@ -404,7 +419,7 @@ errors contains error if {
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.multiple_errors", yamlPath, "001_0006", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.multiple_errors", yamlPath, "001_0006", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -459,7 +474,7 @@ Items:
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.complex", yamlPath, "001_0007", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.complex", yamlPath, "001_0007", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -494,7 +509,7 @@ errors contains error if {
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.time", yamlPath, "001_0008", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.time", yamlPath, "001_0008", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -538,7 +553,7 @@ errors contains error if {
 		}
 
 		// This should not fail due to YAML 1.1 octal interpretation
-		testcase, err := evalTestcase_Rego(regoPath, "data.test.leading_zero", yamlPath, "002_0001", false, tempDir)
+		testcase, err := evalTestcase_Rego(regoPath, "data.test.leading_zero", yamlPath, "002_0001", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase (possibly rulenumber quoting issue): %v", err)
 		}
@@ -549,6 +564,79 @@ errors contains error if {
 	})
 }
 
+func TestPreparedRegoRule_ConcurrentEval(t *testing.T) {
+	tempDir := t.TempDir()
+	regoContent := `# METADATA
+# custom:
+#  rulenumber: "001_0001"
+package test.concurrent
+
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains error if {
+    input.Name == "Bad"
+    error := sprintf("Name %v is not allowed", [input.Name])
+}
+`
+	regoPath := filepath.Join(tempDir, "concurrent.rego")
+	if err := os.WriteFile(regoPath, []byte(regoContent), 0644); err != nil {
+		t.Fatalf("Failed to write rego file: %v", err)
+	}
+	goodPath := filepath.Join(tempDir, "good.yaml")
+	badPath := filepath.Join(tempDir, "bad.yaml")
+	if err := os.WriteFile(goodPath, []byte("Name: Good\n"), 0644); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	if err := os.WriteFile(badPath, []byte("Name: Bad\n"), 0644); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	prepared, err := prepareRegoRule(regoPath, "data.test.concurrent", nil)
+	if err != nil {
+		t.Fatalf("Failed to prepare rule: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 64)
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			inputPath, expectFailure := goodPath, false
+			if i%2 == 1 {
+				inputPath, expectFailure = badPath, true
+			}
+			testcase, err := prepared.evalTestcase(inputPath, "001_0001", false, tempDir)
+			if err != nil {
+				errs <- err.Error()
+				return
+			}
+			if (testcase.Failure != nil) != expectFailure {
+				errs <- "unexpected result for " + inputPath
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for msg := range errs {
+		t.Error(msg)
+	}
+}
+
+func TestPrepareRegoRule_CompileError(t *testing.T) {
+	tempDir := t.TempDir()
+	regoPath := filepath.Join(tempDir, "broken.rego")
+	if err := os.WriteFile(regoPath, []byte("package test.broken\n\nallow if {\n"), 0644); err != nil {
+		t.Fatalf("Failed to write rego file: %v", err)
+	}
+	if _, err := prepareRegoRule(regoPath, "data.test.broken", nil); err == nil {
+		t.Fatal("expected compile error for invalid rule")
+	}
+}
+
 // Helper function to check if string contains substring
 func containsSubstring(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
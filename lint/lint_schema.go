@@ -0,0 +1,261 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
+	"github.com/open-policy-agent/opa/topdown/cache"
+)
+
+// schemaRuleMetadataKey is the custom keyword of a JSON Schema rule that holds
+// the mxlint metadata. Validators ignore unknown keywords.
+const schemaRuleMetadataKey = "x-mxlint"
+
+// schemaRuleSuffix is the file name suffix of JSON Schema rules.
+const schemaRuleSuffix = ".schema.json"
+
+// schemaMatchQuery validates the input with the json.match_schema built-in,
+// which returns [valid, errors].
+const schemaMatchQuery = "json.match_schema(input, data.mxlint.schema)"
+
+// preparedSchemaRule is a JSON Schema rule compiled once and evaluated against
+// many documents. The compiled schema is kept in the inter-query cache of the
+// rule, so it is not compiled again per document.
+type preparedSchemaRule struct {
+	path  string
+	query rego.PreparedEvalQuery
+	cache cache.InterQueryValueCache
+}
+
+func readSchemaRuleFile(rulePath string) (map[string]interface{}, *ruleFileMetadata, error) {
+	content, err := os.ReadFile(rulePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read rule %s: %w", rulePath, err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse rule %s: %w", rulePath, err)
+	}
+	rawMetadata, ok := schema[schemaRuleMetadataKey]
+	if !ok {
+		return nil, nil, fmt.Errorf("%s keyword is required", schemaRuleMetadataKey)
+	}
+	delete(schema, schemaRuleMetadataKey)
+
+	encoded, err := json.Marshal(rawMetadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	var metadata ruleFileMetadata
+	if err := json.Unmarshal(encoded, &metadata); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s keyword: %w", schemaRuleMetadataKey, err)
+	}
+	return schema, &metadata, nil
+}
+
+// prepareSchemaRule reads the schema, checks that it is a valid JSON Schema
+// and prepares the validation query.
+func prepareSchemaRule(rulePath string) (*preparedSchemaRule, error) {
+	schema, _, err := readSchemaRuleFile(rulePath)
+	if err != nil {
+		return nil, err
+	}
+	store := inmem.NewFromObject(map[string]interface{}{
+		"mxlint": map[string]interface{}{
+			"schema": schema,
+		},
+	})
+
+	rs, err := rego.New(
+		rego.Query("json.verify_schema(data.mxlint.schema)"),
+		rego.Store(store),
+	).Eval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile rule %s: %w", rulePath, err)
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return nil, fmt.Errorf("failed to verify the schema of rule %s", rulePath)
+	}
+	if verified, ok := rs[0].Expressions[0].Value.([]interface{}); ok && len(verified) == 2 && verified[0] != true {
+		return nil, fmt.Errorf("invalid schema in rule %s: %v", rulePath, verified[1])
+	}
+
+	query, err := rego.New(
+		rego.Query(schemaMatchQuery),
+		rego.Store(store),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile rule %s: %w", rulePath, err)
+	}
+	return &preparedSchemaRule{
+		path:  rulePath,
+		query: query,
+		cache: cache.NewInterQueryValueCache(context.Background(), nil),
+	}, nil
+}
+
+// validate returns whether input matches the schema and one violation per
+// schema error. document is the input as Go values, used to resolve the
+// paths of the errors.
+func (p *preparedSchemaRule) validate(ctx context.Context, document interface{}, evalOptions ...rego.EvalOption) (bool, []interface{}, error) {
+	evalOptions = append(evalOptions, rego.EvalInterQueryBuiltinValueCache(p.cache))
+	rs, err := p.query.Eval(ctx, evalOptions...)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("query %s returned no result", schemaMatchQuery))
+	}
+	result, ok := rs[0].Expressions[0].Value.([]interface{})
+	if !ok || len(result) != 2 {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("query %s returned %v", schemaMatchQuery, rs[0].Expressions[0].Value))
+	}
+	valid, _ := result[0].(bool)
+	schemaErrors, _ := result[1].([]interface{})
+	violations := make([]interface{}, 0, len(schemaErrors))
+	for _, schemaError := range schemaErrors {
+		violations = append(violations, schemaViolation(schemaError, document))
+	}
+	return valid, violations, nil
+}
+
+// schemaViolation converts a json.match_schema error into a structured
+// violation. The field of the error is the instance path in dot notation,
+// e.g. "Entities.0.Name", or "(root)" for the document itself.
+func schemaViolation(schemaError interface{}, document interface{}) map[string]interface{} {
+	object, _ := schemaError.(map[string]interface{})
+	field, _ := object["field"].(string)
+	description, _ := object["desc"].(string)
+	if field == "" || field == "(root)" {
+		return map[string]interface{}{"message": description}
+	}
+	// Some descriptions, such as those of enum and const, already start
+	// with the field.
+	message := description
+	if !strings.HasPrefix(description, field+" ") && !strings.HasPrefix(description, field+":") {
+		message = fmt.Sprintf("%s: %s", field, description)
+	}
+	return map[string]interface{}{
+		"message": message,
+		"path":    schemaPointer(field, document),
+	}
+}
+
+// schemaPointer converts the dotted instance path of a schema error into a
+// JSON pointer. Property names may contain dots, so the segments are found
+// by walking the document; a path that is not in the document is split on
+// every dot.
+func schemaPointer(field string, document interface{}) string {
+	segments, ok := schemaPathSegments(field, document)
+	if !ok {
+		segments = strings.Split(field, ".")
+	}
+	var pointer strings.Builder
+	for _, segment := range segments {
+		pointer.WriteString("/" + escapeJSONPointer(segment))
+	}
+	return pointer.String()
+}
+
+func schemaPathSegments(field string, value interface{}) ([]string, bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		// Longer keys first, so "a.b" is preferred over "a" with a child "b".
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			if field == key || strings.HasPrefix(field, key+".") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return len(keys[i]) > len(keys[j])
+		})
+		for _, key := range keys {
+			if field == key {
+				return []string{key}, true
+			}
+			if rest, ok := schemaPathSegments(field[len(key)+1:], value[key]); ok {
+				return append([]string{key}, rest...), true
+			}
+		}
+	case []interface{}:
+		token, rest, nested := strings.Cut(field, ".")
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(value) {
+			return nil, false
+		}
+		if !nested {
+			return []string{token}, true
+		}
+		if segments, ok := schemaPathSegments(rest, value[index]); ok {
+			return append([]string{token}, segments...), true
+		}
+	}
+	return nil, false
+}
+
+func (p *preparedSchemaRule) evalDocument(document *modelDocument, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	rulePath := p.path
+	inputFilePath := document.path
+	data, node, err := document.yamlDocument()
+	if err != nil {
+		log.Errorf("Error reading YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
+		return nil, err
+	}
+	input, err := document.regoValue()
+	if err != nil {
+		log.Errorf("Error converting YAML file %q (rule: %q): %s\n", inputFilePath, rulePath, err)
+		return nil, err
+	}
+
+	doc, _ := data[documentationKey].(string)
+	shouldSkip, reason := shouldSkipRule(doc, ruleNumber, ignoreNoqa, inputFilePath, modelSourcePath)
+	if shouldSkip {
+		return &Testcase{
+			Name:    inputFilePath,
+			Time:    0,
+			Skipped: &Skipped{Message: reason},
+		}, nil
+	}
+
+	ctx := context.Background()
+	if timeout := ruleTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	startTime := time.Now()
+	valid, violations, err := p.validate(ctx, data, rego.EvalParsedInput(input))
+	duration := time.Since(startTime)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return newTimeoutTestcase(rulePath, inputFilePath, duration), nil
+		}
+		testcase := newRuleErrorTestcase(rulePath, inputFilePath, err, duration)
+		log.Error(testcase.Error.Message)
+		return testcase, nil
+	}
+
+	return newRuleResultTestcase(inputFilePath, data, node, ruleNumber, ignoreNoqa, valid, violations, duration), nil
+}
+
+func parseRuleMetadata_Schema(rulePath string) (*Rule, error) {
+
+	log.Debugf("reading rule %s", rulePath)
+
+	_, metadata, err := readSchemaRuleFile(rulePath)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.rule(rulePath, LanguageJSONSchema)
+}
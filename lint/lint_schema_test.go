@@ -0,0 +1,252 @@
+package lint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSchemaRule = `{
+  "x-mxlint": {
+    "title": "Entities are well formed",
+    "description": "Entity names are capitalized and entities have attributes.",
+    "custom": {
+      "category": "Maintainability",
+      "rulenumber": "002_0201",
+      "severity": "LOW",
+      "input": ".*DomainModel\\.yaml"
+    }
+  },
+  "type": "object",
+  "properties": {
+    "Entities": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["Attributes"],
+        "properties": {
+          "Name": { "type": "string", "pattern": "^[A-Z]" }
+        }
+      }
+    }
+  }
+}`
+
+// evalTestcase_Schema evaluates a JSON Schema rule on a single document.
+func evalTestcase_Schema(rulePath string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	prepared, err := prepareSchemaRule(rulePath)
+	if err != nil {
+		return nil, err
+	}
+	return prepared.evalDocument(newModelDocument(inputFilePath, ""), ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
+func TestParseRuleMetadata_Schema(t *testing.T) {
+	rulePath := writeTestRule(t, "002_0201_entities.schema.json", testSchemaRule)
+	rule, err := parseRuleMetadata_Schema(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to parse rule metadata: %v", err)
+	}
+	if rule.Language != LanguageJSONSchema || rule.RuleNumber != "002_0201" || rule.Pattern != ".*DomainModel\\.yaml" {
+		t.Fatalf("unexpected rule metadata: %+v", rule)
+	}
+
+	missing := writeTestRule(t, "002_0202_missing.schema.json", `{"type": "object"}`)
+	if _, err := parseRuleMetadata_Schema(missing); err == nil || !strings.Contains(err.Error(), schemaRuleMetadataKey) {
+		t.Fatalf("expected a schema without %s to be rejected, got: %v", schemaRuleMetadataKey, err)
+	}
+}
+
+func TestEvalTestcase_Schema(t *testing.T) {
+	rulePath := writeTestRule(t, "002_0201_entities.schema.json", testSchemaRule)
+	modelDir := t.TempDir()
+	documents := map[string]string{
+		"Valid.yaml":   "Entities:\n  - Name: Customer\n    Attributes: []\n",
+		"Invalid.yaml": "Entities:\n  - Name: Customer\n    Attributes: []\n  - Name: order\n",
+	}
+	for name, content := range documents {
+		if err := writeTestFile(modelDir, name, content); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	testcase, err := evalTestcase_Schema(rulePath, filepath.Join(modelDir, "Valid.yaml"), "002_0201", false, modelDir)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testcase.Failure != nil {
+		t.Fatalf("expected Valid.yaml to pass, got %+v", testcase.Failure)
+	}
+
+	testcase, err = evalTestcase_Schema(rulePath, filepath.Join(modelDir, "Invalid.yaml"), "002_0201", false, modelDir)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testcase.Failure == nil || len(testcase.Violations) != 2 {
+		t.Fatalf("expected two schema violations, got %+v", testcase)
+	}
+	paths := map[string]int{}
+	messages := map[string]string{}
+	for _, violation := range testcase.Violations {
+		paths[violation.Path] = violation.Line
+		messages[violation.Path] = violation.Message
+	}
+	if line, ok := paths["/Entities/1"]; !ok || line != 4 {
+		t.Fatalf("expected the missing Attributes on /Entities/1 at line 4, got %+v", testcase.Violations)
+	}
+	if _, ok := paths["/Entities/1/Name"]; !ok {
+		t.Fatalf("expected the Name pattern violation on /Entities/1/Name, got %+v", testcase.Violations)
+	}
+	if messages["/Entities/1"] != "Entities.1: Attributes is required" || messages["/Entities/1/Name"] != "Entities.1.Name: Does not match pattern '^[A-Z]'" {
+		t.Fatalf("unexpected violation messages %+v", messages)
+	}
+}
+
+func TestEvalTestcase_SchemaFieldInDescription(t *testing.T) {
+	rulePath := writeTestRule(t, "002_0204_security.schema.json", `{
+  "x-mxlint": {
+    "title": "Security level is production",
+    "description": "Security level is production",
+    "custom": { "rulenumber": "002_0204", "input": ".*\\.yaml" }
+  },
+  "properties": {
+    "SecurityLevel": { "const": "CheckEverything" },
+    "Mode": { "enum": ["Production"] }
+  }
+}`)
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "Security.yaml", "SecurityLevel: CheckNothing\nMode: Prototype\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	testcase, err := evalTestcase_Schema(rulePath, filepath.Join(modelDir, "Security.yaml"), "002_0204", false, modelDir)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	messages := map[string]string{}
+	for _, violation := range testcase.Violations {
+		messages[violation.Path] = violation.Message
+	}
+	if messages["/SecurityLevel"] != `SecurityLevel does not match: "CheckEverything"` {
+		t.Fatalf("expected the const message without a repeated field, got %q", messages["/SecurityLevel"])
+	}
+	if messages["/Mode"] != `Mode must be one of the following: "Production"` {
+		t.Fatalf("expected the enum message without a repeated field, got %q", messages["/Mode"])
+	}
+}
+
+func TestSchemaPointer(t *testing.T) {
+	document := map[string]interface{}{
+		"Entities": []interface{}{
+			map[string]interface{}{"Name": "Customer"},
+		},
+		"App.Settings": map[string]interface{}{
+			"a/b": "x",
+			"m~n": map[string]interface{}{"c.d": 1},
+		},
+		"App": map[string]interface{}{"Settings": "shadowed"},
+	}
+	tests := map[string]string{
+		"Entities.0.Name":      "/Entities/0/Name",
+		"App.Settings.a/b":     "/App.Settings/a~1b",
+		"App.Settings.m~n.c.d": "/App.Settings/m~0n/c.d",
+		"App.Settings":         "/App.Settings",
+		// Paths that are not in the document, such as missing properties.
+		"Entities.3.Name": "/Entities/3/Name",
+		"Missing.Name":    "/Missing/Name",
+	}
+	for field, expected := range tests {
+		if pointer := schemaPointer(field, document); pointer != expected {
+			t.Errorf("%s: expected %s, got %s", field, expected, pointer)
+		}
+	}
+}
+
+func TestEvalTestcase_SchemaEscapedPaths(t *testing.T) {
+	rulePath := writeTestRule(t, "002_0202_settings.schema.json", `{
+  "x-mxlint": {
+    "title": "Settings are strings",
+    "description": "Settings are strings",
+    "custom": { "rulenumber": "002_0202", "input": ".*\\.yaml" }
+  },
+  "properties": {
+    "App.Settings": {
+      "properties": {
+        "a/b": { "type": "string" },
+        "m~n": { "type": "string" }
+      }
+    }
+  }
+}`)
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "Settings.yaml", "App.Settings:\n  a/b: 1\n  m~n: 2\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	testcase, err := evalTestcase_Schema(rulePath, filepath.Join(modelDir, "Settings.yaml"), "002_0202", false, modelDir)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	lines := map[string]int{}
+	for _, violation := range testcase.Violations {
+		lines[violation.Path] = violation.Line
+	}
+	if lines["/App.Settings/a~1b"] != 2 || lines["/App.Settings/m~0n"] != 3 {
+		t.Fatalf("expected escaped pointers resolved to their lines, got %+v", testcase.Violations)
+	}
+}
+
+func TestPrepareSchemaRule_InvalidSchema(t *testing.T) {
+	rulePath := writeTestRule(t, "002_0203_invalid.schema.json", `{
+  "x-mxlint": {"title": "Invalid", "description": "Invalid", "custom": {"rulenumber": "002_0203"}},
+  "type": "objekt"
+}`)
+	if _, err := prepareSchemaRule(rulePath); err == nil || !strings.Contains(err.Error(), "invalid schema") {
+		t.Fatalf("expected an invalid schema to be rejected, got: %v", err)
+	}
+}
+
+func TestEvalTestsuite_SchemaRule(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{
+		Lint: ConfigLintSpec{
+			Skip: map[string][]ConfigSkipRule{
+				"Skipped": {{Rule: "002_0201", Reason: "legacy"}},
+			},
+		},
+	})
+
+	modelDir := t.TempDir()
+	for _, name := range []string{"Failing.yaml", "Skipped.yaml"} {
+		if err := writeTestFile(modelDir, name, "Entities:\n  - Name: order\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+	rule := Rule{
+		Path:       writeTestRule(t, "002_0201_entities.schema.json", testSchemaRule),
+		RuleNumber: "002_0201",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJSONSchema,
+	}
+
+	testsuite, err := evalTestsuite(rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate testsuite: %v", err)
+	}
+	if testsuite.Failures != 1 || testsuite.Skipped != 1 || testsuite.Errors != 0 {
+		t.Fatalf("expected one failure and one skipped document, got %+v", testsuite)
+	}
+}
+
+func TestRunSchemaTestCases(t *testing.T) {
+	rulesDir := t.TempDir()
+	if err := writeTestFile(rulesDir, "002_0201_entities.schema.json", testSchemaRule); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	if err := writeTestFile(rulesDir, "002_0201_entities_test.yaml", "TestCases:\n- name: allow\n  input:\n    Entities:\n      - Name: Customer\n        Attributes: []\n  allow: true\n- name: no_allow\n  input:\n    Entities:\n      - Name: customer\n  allow: false\n"); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	if err := TestAll(rulesDir); err != nil {
+		t.Fatalf("Expected rule tests to pass: %v", err)
+	}
+}
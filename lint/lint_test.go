@@ -8,6 +8,19 @@ import (
 	"testing"
 )
 
+// evalTestsuite evaluates a single rule without applying the result policy.
+func evalTestsuite(rule Rule, modelSourcePath string, ignoreNoqa bool, useCache bool, changedFiles []string, originalPathMap map[string]string) (*Testsuite, error) {
+	run, err := newLintRun(modelSourcePath, ignoreNoqa, useCache, changedFiles, originalPathMap)
+	if err != nil {
+		return nil, err
+	}
+	testsuites, err := run.evalTestsuites([]Rule{rule}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &testsuites[0], nil
+}
+
 // assertNonNegativeTime checks elapsed timing. On Windows, sub-tick work can
 // legitimately report 0; elsewhere we still require a positive value.
 func assertNonNegativeTime(t *testing.T, label string, seconds float64) {
@@ -216,21 +229,21 @@ Name: "Test"
 		Language:    LanguageJavascript,
 	}
 
-	t.Run("documentation noqa is ignored", func(t *testing.T) {
+	t.Run("documentation noqa skips the rule", func(t *testing.T) {
 		result, err := evalTestsuite(rule, tempDir, false, false, nil, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testsuite: %v", err)
 		}
 
-		if result.Skipped != 0 {
-			t.Errorf("Expected 0 skipped, got %d", result.Skipped)
+		if result.Skipped != 1 {
+			t.Errorf("Expected 1 skipped, got %d", result.Skipped)
 		}
-		if result.Failures != 1 {
-			t.Errorf("Expected 1 failure when documentation noqa is ignored, got %d", result.Failures)
+		if result.Failures != 0 {
+			t.Errorf("Expected 0 failures when documentation noqa applies, got %d", result.Failures)
 		}
 	})
 
-	t.Run("ignoreNoqa has no effect on documentation skip", func(t *testing.T) {
+	t.Run("ignoreNoqa disables documentation noqa", func(t *testing.T) {
 		result, err := evalTestsuite(rule, tempDir, true, false, nil, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testsuite: %v", err)
@@ -401,6 +414,8 @@ func TestReadRulesMetadata(t *testing.T) {
 		hasRego := false
 		hasJS := false
 		hasTS := false
+		hasYaml := false
+		hasSchema := false
 		for _, rule := range rules {
 			switch rule.Language {
 			case LanguageRego:
@@ -409,6 +424,10 @@ func TestReadRulesMetadata(t *testing.T) {
 				hasJS = true
 			case LanguageTypescript:
 				hasTS = true
+			case LanguageYaml:
+				hasYaml = true
+			case LanguageJSONSchema:
+				hasSchema = true
 			}
 		}
 
@@ -418,6 +437,12 @@ func TestReadRulesMetadata(t *testing.T) {
 		if !hasJS {
 			t.Error("Expected at least one JavaScript rule")
 		}
+		if !hasYaml {
+			t.Error("Expected at least one YAML rule")
+		}
+		if !hasSchema {
+			t.Error("Expected at least one JSON Schema rule")
+		}
 		if !hasTS {
 			t.Error("Expected at least one TypeScript rule")
 		}
@@ -497,7 +522,7 @@ function rule(input) {
 
 func TestEvalAll(t *testing.T) {
 	t.Run("all rules pass", func(t *testing.T) {
-		err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", "", "", false, false, nil)
+		err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", "", "", "", false, false, nil)
 		if err != nil {
 			t.Errorf("Expected no failures: %v", err)
 		}
@@ -507,7 +532,7 @@ func TestEvalAll(t *testing.T) {
 		tempDir := t.TempDir()
 		xunitPath := filepath.Join(tempDir, "report.xml")
 
-		err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", xunitPath, "", false, false, nil)
+		err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", xunitPath, "", "", false, false, nil)
 		if err != nil {
 			t.Errorf("Expected no failures: %v", err)
 		}
@@ -522,7 +547,7 @@ func TestEvalAll(t *testing.T) {
 		tempDir := t.TempDir()
 		jsonPath := filepath.Join(tempDir, "report.json")
 
-		err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", "", jsonPath, false, false, nil)
+		err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", "", jsonPath, "", false, false, nil)
 		if err != nil {
 			t.Errorf("Expected no failures: %v", err)
 		}
@@ -536,7 +561,7 @@ func TestEvalAll(t *testing.T) {
 
 func TestEvalAllWithResults(t *testing.T) {
 	t.Run("returns results", func(t *testing.T) {
-		result, err := EvalAllWithResults("./../resources/rules", "./../resources/modelsource-v1", "", "", false, false, nil)
+		result, err := EvalAllWithResults("./../resources/rules", "./../resources/modelsource-v1", "", "", "", false, false, nil)
 		if err != nil {
 			t.Errorf("Expected no failures: %v", err)
 		}
@@ -7,6 +7,15 @@ import (
 	"testing"
 )
 
+// evalTestcase_Typescript evaluates a TypeScript rule on a single document.
+func evalTestcase_Typescript(rulePath string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string, settings map[string]interface{}) (*Testcase, error) {
+	compiled, err := prepareTypescriptRule(rulePath, settings)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.evalTestcase(inputFilePath, ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
 func TestHashRuleContent(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -312,7 +321,7 @@ function rule(input) {
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0001", false, tempDir)
+		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0001", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -353,7 +362,7 @@ function rule(input) {
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0002", false, tempDir)
+		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0002", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -365,7 +374,7 @@ function rule(input) {
 		}
 	})
 
-	t.Run("documentation noqa is ignored", func(t *testing.T) {
+	t.Run("documentation noqa skips the rule", func(t *testing.T) {
 		tsContent := `
 const metadata = {
     title: "Test Rule",
@@ -392,20 +401,20 @@ Name: "Test"
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0003", false, tempDir)
+		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0003", false, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
 
-		if testcase.Skipped != nil {
-			t.Error("Expected testcase not to be skipped")
+		if testcase.Skipped == nil || !strings.Contains(testcase.Skipped.Message, "#noqa:001_0003") {
+			t.Errorf("Expected testcase to be skipped by the noqa directive, got %+v", testcase.Skipped)
 		}
-		if testcase.Failure == nil {
-			t.Error("Expected failure when documentation noqa is ignored")
+		if testcase.Failure != nil {
+			t.Error("Expected no failure for a skipped testcase")
 		}
 	})
 
-	t.Run("ignoreNoqa has no effect on documentation skip", func(t *testing.T) {
+	t.Run("ignoreNoqa disables documentation noqa", func(t *testing.T) {
 		tsContent := `
 const metadata = {
     title: "Test Rule",
@@ -432,7 +441,7 @@ Name: "Test"
 			t.Fatalf("Failed to write yaml file: %v", err)
 		}
 
-		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0004", true, tempDir)
+		testcase, err := evalTestcase_Typescript(tsPath, yamlPath, "001_0004", true, tempDir, nil)
 		if err != nil {
 			t.Fatalf("Failed to evaluate testcase: %v", err)
 		}
@@ -456,7 +465,7 @@ function rule(input) { return { allow: true, errors: [] }; }
 			t.Fatalf("Failed to write rule file: %v", err)
 		}
 
-		_, err = evalTestcase_Typescript(tsPath, filepath.Join(tempDir, "nonexistent.yaml"), "001_0005", false, tempDir)
+		_, err = evalTestcase_Typescript(tsPath, filepath.Join(tempDir, "nonexistent.yaml"), "001_0005", false, tempDir, nil)
 		if err == nil {
 			t.Error("Expected error for nonexistent input file")
 		}
@@ -509,7 +518,7 @@ TestCases:
 			Language: LanguageTypescript,
 		}
 
-		err = runTypescriptTestCases(rule)
+		_, err = runTestCases(rule, 0)
 		if err != nil {
 			t.Errorf("Expected test cases to pass, got error: %v", err)
 		}
@@ -550,7 +559,7 @@ TestCases:
 			Language: LanguageTypescript,
 		}
 
-		err = runTypescriptTestCases(rule)
+		_, err = runTestCases(rule, 0)
 		if err == nil {
 			t.Error("Expected test cases to fail")
 		}
@@ -568,7 +577,7 @@ TestCases:
 			Language: LanguageTypescript,
 		}
 
-		err = runTypescriptTestCases(rule)
+		_, err = runTestCases(rule, 0)
 		if err == nil {
 			t.Error("Expected error when test file is missing")
 		}
@@ -0,0 +1,505 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Operators of YAML rule assertions.
+const (
+	assertEquals         = "equals"
+	assertNotEquals      = "notEquals"
+	assertMatches        = "matches"
+	assertNotMatches     = "notMatches"
+	assertIn             = "in"
+	assertNotIn          = "notIn"
+	assertLessThan       = "lessThan"
+	assertLessOrEqual    = "lessOrEqual"
+	assertGreaterThan    = "greaterThan"
+	assertGreaterOrEqual = "greaterOrEqual"
+	assertExists         = "exists"
+	assertNotExists      = "notExists"
+	assertMinCount       = "minCount"
+	assertMaxCount       = "maxCount"
+)
+
+// ruleFileMetadata is the metadata block of rule languages that are data
+// files rather than code: YAML rules and JSON Schema rules.
+type ruleFileMetadata struct {
+	Title       string `yaml:"title" json:"title"`
+	Description string `yaml:"description" json:"description"`
+	Custom      struct {
+		Category    string                 `yaml:"category" json:"category"`
+		RuleName    string                 `yaml:"rulename" json:"rulename"`
+		Severity    string                 `yaml:"severity" json:"severity"`
+		RuleNumber  string                 `yaml:"rulenumber" json:"rulenumber"`
+		Remediation string                 `yaml:"remediation" json:"remediation"`
+		Input       string                 `yaml:"input" json:"input"`
+		Scope       string                 `yaml:"scope" json:"scope"`
+		Settings    map[string]interface{} `yaml:"settings" json:"settings"`
+	} `yaml:"custom" json:"custom"`
+}
+
+// rule validates the metadata and returns the rule it describes. Only
+// document scope is supported.
+func (m ruleFileMetadata) rule(rulePath string, language string) (*Rule, error) {
+	if strings.TrimSpace(m.Title) == "" || strings.TrimSpace(m.Description) == "" {
+		return nil, fmt.Errorf("metadata.title and metadata.description are required")
+	}
+	if strings.TrimSpace(m.Custom.RuleNumber) == "" {
+		return nil, fmt.Errorf("metadata.custom.rulenumber is required")
+	}
+	scope := normalizeRuleScope(m.Custom.Scope, rulePath)
+	if scope == ScopeProject {
+		return nil, fmt.Errorf("project scope is not supported for %s rules", language)
+	}
+	return &Rule{
+		Title:       m.Title,
+		Description: m.Description,
+		Category:    m.Custom.Category,
+		Severity:    m.Custom.Severity,
+		RuleNumber:  m.Custom.RuleNumber,
+		Remediation: m.Custom.Remediation,
+		RuleName:    m.Custom.RuleName,
+		Path:        rulePath,
+		Pattern:     m.Custom.Input,
+		PackageName: rulePath,
+		Language:    language,
+		Scope:       scope,
+		Settings:    m.Custom.Settings,
+	}, nil
+}
+
+// yamlRuleFile is a declarative rule: the metadata block shared with the other
+// languages and a list of assertions on the document.
+type yamlRuleFile struct {
+	Metadata   ruleFileMetadata `yaml:"metadata"`
+	Assertions []yamlAssertion  `yaml:"assertions"`
+}
+
+// yamlAssertion checks the values selected by Path. The expected value is
+// Value, or the rule setting named by Setting. Message may use {path},
+// {value} and {expected}.
+type yamlAssertion struct {
+	Path     string      `yaml:"path"`
+	Operator string      `yaml:"operator"`
+	Value    interface{} `yaml:"value"`
+	Setting  string      `yaml:"setting"`
+	Message  string      `yaml:"message"`
+}
+
+// yamlPathSegment is one step of an assertion path: a key, an index or a
+// wildcard over all keys or items.
+type yamlPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// yamlPathMatch is a value selected by an assertion path and its JSON pointer.
+type yamlPathMatch struct {
+	pointer string
+	value   interface{}
+}
+
+type compiledYamlAssertion struct {
+	yamlAssertion
+	segments []yamlPathSegment
+	expected interface{}
+	pattern  *regexp.Regexp
+}
+
+type compiledYamlRule struct {
+	path       string
+	assertions []compiledYamlAssertion
+}
+
+func readYamlRuleFile(rulePath string) (*yamlRuleFile, error) {
+	content, err := os.ReadFile(rulePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule %s: %w", rulePath, err)
+	}
+	var ruleFile yamlRuleFile
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&ruleFile); err != nil {
+		return nil, fmt.Errorf("failed to parse rule %s: %w", rulePath, err)
+	}
+	return &ruleFile, nil
+}
+
+// isYamlRuleFile reports whether a .yaml file in the rules directory is a
+// rule. Other YAML files, such as rule test cases or ruleset configuration,
+// have no top-level assertions key. Files with a syntax error are treated as
+// rules, so that reading them reports the error instead of dropping the rule.
+func isYamlRuleFile(rulePath string) bool {
+	content, err := os.ReadFile(rulePath)
+	if err != nil {
+		return true
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return true
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return false
+	}
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "assertions" {
+			return true
+		}
+	}
+	return false
+}
+
+// prepareYamlRule reads a YAML rule and compiles its paths, patterns and
+// expected values.
+func prepareYamlRule(rulePath string, settings map[string]interface{}) (*compiledYamlRule, error) {
+	ruleFile, err := readYamlRuleFile(rulePath)
+	if err != nil {
+		return nil, err
+	}
+	compiled := &compiledYamlRule{path: rulePath}
+	for i, assertion := range ruleFile.Assertions {
+		compiledAssertion, err := compileYamlAssertion(assertion, settings)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assertion %d of rule %s: %w", i+1, rulePath, err)
+		}
+		compiled.assertions = append(compiled.assertions, compiledAssertion)
+	}
+	return compiled, nil
+}
+
+func compileYamlAssertion(assertion yamlAssertion, settings map[string]interface{}) (compiledYamlAssertion, error) {
+	compiled := compiledYamlAssertion{yamlAssertion: assertion}
+	segments, err := parseYamlRulePath(assertion.Path)
+	if err != nil {
+		return compiled, err
+	}
+	compiled.segments = segments
+
+	compiled.expected = normalizeAssertionValue(assertion.Value)
+	if assertion.Setting != "" {
+		value, ok := settings[assertion.Setting]
+		if !ok {
+			return compiled, fmt.Errorf("setting %q is not declared in custom.settings", assertion.Setting)
+		}
+		compiled.expected = value
+	}
+
+	switch assertion.Operator {
+	case assertEquals, assertNotEquals:
+	case assertMatches, assertNotMatches:
+		expression, ok := compiled.expected.(string)
+		if !ok {
+			return compiled, fmt.Errorf("%s expects a regular expression", assertion.Operator)
+		}
+		if compiled.pattern, err = regexp.Compile(expression); err != nil {
+			return compiled, fmt.Errorf("invalid regular expression %q: %w", expression, err)
+		}
+	case assertIn, assertNotIn:
+		if _, ok := compiled.expected.([]interface{}); !ok {
+			return compiled, fmt.Errorf("%s expects a list", assertion.Operator)
+		}
+	case assertLessThan, assertLessOrEqual, assertGreaterThan, assertGreaterOrEqual, assertMinCount, assertMaxCount:
+		if _, ok := assertionNumber(compiled.expected); !ok {
+			return compiled, fmt.Errorf("%s expects a number", assertion.Operator)
+		}
+	case assertExists, assertNotExists:
+	default:
+		return compiled, fmt.Errorf("unknown operator %q", assertion.Operator)
+	}
+	return compiled, nil
+}
+
+// parseYamlRulePath parses a JSONPath-style expression: $ followed by .key,
+// ['key'], [n], .* or [*].
+func parseYamlRulePath(expression string) ([]yamlPathSegment, error) {
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("path %q must start with $", expression)
+	}
+	segments := make([]yamlPathSegment, 0)
+	rest := expression[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".*"):
+			segments = append(segments, yamlPathSegment{wildcard: true})
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "[*]"):
+			segments = append(segments, yamlPathSegment{wildcard: true})
+			rest = rest[3:]
+		case strings.HasPrefix(rest, "."):
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, fmt.Errorf("path %q has an empty key", expression)
+			}
+			segments = append(segments, yamlPathSegment{key: key})
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "['"), strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has an unterminated key", expression)
+			}
+			segments = append(segments, yamlPathSegment{key: rest[2 : end+2]})
+			rest = rest[end+4:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("path %q has an unterminated index", expression)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q has an invalid index %q", expression, rest[1:end])
+			}
+			segments = append(segments, yamlPathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("path %q is invalid at %q", expression, rest)
+		}
+	}
+	return segments, nil
+}
+
+// selectYamlPath returns the values selected by segments, in document order,
+// with their JSON pointers.
+func selectYamlPath(data interface{}, segments []yamlPathSegment) []yamlPathMatch {
+	matches := []yamlPathMatch{{pointer: "", value: data}}
+	for _, segment := range segments {
+		next := make([]yamlPathMatch, 0, len(matches))
+		for _, match := range matches {
+			switch value := match.value.(type) {
+			case map[string]interface{}:
+				if segment.isIndex {
+					continue
+				}
+				if segment.wildcard {
+					keys := make([]string, 0, len(value))
+					for key := range value {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, yamlPathMatch{pointer: match.pointer + "/" + escapeJSONPointer(key), value: value[key]})
+					}
+					continue
+				}
+				if child, ok := value[segment.key]; ok {
+					next = append(next, yamlPathMatch{pointer: match.pointer + "/" + escapeJSONPointer(segment.key), value: child})
+				}
+			case []interface{}:
+				if segment.wildcard {
+					for i, child := range value {
+						next = append(next, yamlPathMatch{pointer: match.pointer + "/" + strconv.Itoa(i), value: child})
+					}
+					continue
+				}
+				if segment.isIndex && segment.index < len(value) {
+					next = append(next, yamlPathMatch{pointer: match.pointer + "/" + strconv.Itoa(segment.index), value: value[segment.index]})
+				}
+			}
+		}
+		matches = next
+	}
+	return matches
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// normalizeAssertionValue converts a value to JSON types, so that integers
+// from YAML documents compare equal to the float64 numbers of settings.
+func normalizeAssertionValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func assertionNumber(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case float64:
+		return number, true
+	case int:
+		return float64(number), true
+	}
+	return 0, false
+}
+
+func formatAssertionValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// evaluate returns whether the document passes every assertion and one
+// violation per failing value.
+func (c *compiledYamlRule) evaluate(data map[string]interface{}) (bool, []interface{}) {
+	errors := make([]interface{}, 0)
+	for _, assertion := range c.assertions {
+		errors = append(errors, assertion.evaluate(data)...)
+	}
+	return len(errors) == 0, errors
+}
+
+func (a compiledYamlAssertion) evaluate(data map[string]interface{}) []interface{} {
+	matches := selectYamlPath(data, a.segments)
+	violations := make([]interface{}, 0)
+	switch a.Operator {
+	case assertExists:
+		if len(matches) == 0 {
+			violations = append(violations, a.violation("", nil))
+		}
+		return violations
+	case assertNotExists:
+		for _, match := range matches {
+			violations = append(violations, a.violation(match.pointer, match.value))
+		}
+		return violations
+	case assertMinCount, assertMaxCount:
+		limit, _ := assertionNumber(a.expected)
+		count := float64(len(matches))
+		if (a.Operator == assertMinCount && count < limit) || (a.Operator == assertMaxCount && count > limit) {
+			violations = append(violations, a.violation("", len(matches)))
+		}
+		return violations
+	}
+	for _, match := range matches {
+		if !a.holds(normalizeAssertionValue(match.value)) {
+			violations = append(violations, a.violation(match.pointer, match.value))
+		}
+	}
+	return violations
+}
+
+// holds checks a single value against the assertion.
+func (a compiledYamlAssertion) holds(value interface{}) bool {
+	switch a.Operator {
+	case assertEquals:
+		return reflect.DeepEqual(value, a.expected)
+	case assertNotEquals:
+		return !reflect.DeepEqual(value, a.expected)
+	case assertMatches, assertNotMatches:
+		text, ok := value.(string)
+		if !ok {
+			text = formatAssertionValue(value)
+		}
+		return a.pattern.MatchString(text) == (a.Operator == assertMatches)
+	case assertIn, assertNotIn:
+		found := false
+		for _, candidate := range a.expected.([]interface{}) {
+			if reflect.DeepEqual(value, candidate) {
+				found = true
+				break
+			}
+		}
+		return found == (a.Operator == assertIn)
+	}
+	number, ok := assertionNumber(value)
+	if !ok {
+		return false
+	}
+	limit, _ := assertionNumber(a.expected)
+	switch a.Operator {
+	case assertLessThan:
+		return number < limit
+	case assertLessOrEqual:
+		return number <= limit
+	case assertGreaterThan:
+		return number > limit
+	case assertGreaterOrEqual:
+		return number >= limit
+	}
+	return false
+}
+
+func (a compiledYamlAssertion) violation(pointer string, value interface{}) map[string]interface{} {
+	message := a.Message
+	if message == "" {
+		message = fmt.Sprintf("%s %s %s", a.Path, a.Operator, formatAssertionValue(a.expected))
+		if a.Operator == assertExists || a.Operator == assertNotExists {
+			message = fmt.Sprintf("%s %s", a.Path, a.Operator)
+		}
+		if value != nil {
+			message += fmt.Sprintf(", got %s", formatAssertionValue(value))
+		}
+	}
+	message = strings.NewReplacer(
+		"{path}", pointer,
+		"{value}", formatAssertionPlaceholder(value),
+		"{expected}", formatAssertionPlaceholder(a.expected),
+	).Replace(message)
+	violation := map[string]interface{}{"message": message}
+	if pointer != "" {
+		violation["path"] = pointer
+	}
+	return violation
+}
+
+func formatAssertionPlaceholder(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return formatAssertionValue(value)
+}
+
+func (c *compiledYamlRule) evalDocument(document *modelDocument, ruleNumber string, ignoreNoqa bool, modelSourcePath string) (*Testcase, error) {
+	inputFilePath := document.path
+	data, node, err := document.yamlDocument()
+	if err != nil {
+		log.Errorf("Error reading YAML file %q (rule: %q): %s\n", inputFilePath, c.path, err)
+		return nil, err
+	}
+
+	doc, _ := data[documentationKey].(string)
+	shouldSkip, reason := shouldSkipRule(doc, ruleNumber, ignoreNoqa, inputFilePath, modelSourcePath)
+	if shouldSkip {
+		return &Testcase{
+			Name:    inputFilePath,
+			Time:    0,
+			Skipped: &Skipped{Message: reason},
+		}, nil
+	}
+
+	startTime := time.Now()
+	allow, errors := c.evaluate(data)
+	return newRuleResultTestcase(inputFilePath, data, node, ruleNumber, ignoreNoqa, allow, errors, time.Since(startTime)), nil
+}
+
+func parseRuleMetadata_Yaml(rulePath string) (*Rule, error) {
+
+	log.Debugf("reading rule %s", rulePath)
+
+	ruleFile, err := readYamlRuleFile(rulePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(ruleFile.Assertions) == 0 {
+		return nil, fmt.Errorf("at least one assertion is required")
+	}
+	return ruleFile.Metadata.rule(rulePath, LanguageYaml)
+}
@@ -0,0 +1,222 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testYamlRule = `metadata:
+  title: Entities are well formed
+  description: Entity and attribute names follow the conventions.
+  custom:
+    category: Maintainability
+    rulenumber: "002_0101"
+    severity: LOW
+    input: .*DomainModel\.yaml
+    settings:
+      maxEntities: 2
+assertions:
+  - path: $.Entities[*]
+    operator: maxCount
+    setting: maxEntities
+  - path: $.Entities[*].Attributes[*].Name
+    operator: matches
+    value: ^[A-Z]
+    message: Attribute {value} must start with an uppercase letter
+`
+
+// evalTestcase_Yaml evaluates a YAML rule on a single document.
+func evalTestcase_Yaml(rulePath string, inputFilePath string, ruleNumber string, ignoreNoqa bool, modelSourcePath string, settings map[string]interface{}) (*Testcase, error) {
+	compiled, err := prepareYamlRule(rulePath, settings)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.evalDocument(newModelDocument(inputFilePath, ""), ruleNumber, ignoreNoqa, modelSourcePath)
+}
+
+func TestParseYamlRulePath(t *testing.T) {
+	data := map[string]interface{}{
+		"Entities": []interface{}{
+			map[string]interface{}{"Name": "Customer", "a/b": 1},
+			map[string]interface{}{"Name": "Order"},
+		},
+	}
+	tests := []struct {
+		path     string
+		pointers string
+	}{
+		{path: "$", pointers: ""},
+		{path: "$.Entities[*].Name", pointers: "/Entities/0/Name,/Entities/1/Name"},
+		{path: "$.Entities[1].Name", pointers: "/Entities/1/Name"},
+		{path: "$['Entities'][0]['a/b']", pointers: "/Entities/0/a~1b"},
+		{path: "$.Entities[0].*", pointers: "/Entities/0/Name,/Entities/0/a~1b"},
+		{path: "$.Missing.Name", pointers: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			segments, err := parseYamlRulePath(tt.path)
+			if err != nil {
+				t.Fatalf("Failed to parse path: %v", err)
+			}
+			pointers := make([]string, 0)
+			for _, match := range selectYamlPath(data, segments) {
+				pointers = append(pointers, match.pointer)
+			}
+			if strings.Join(pointers, ",") != tt.pointers {
+				t.Fatalf("expected %q, got %q", tt.pointers, strings.Join(pointers, ","))
+			}
+		})
+	}
+
+	for _, invalid := range []string{"Entities", "$.Entities[x]", "$.Entities[0", "$..Name"} {
+		if _, err := parseYamlRulePath(invalid); err == nil {
+			t.Errorf("expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestYamlAssertionOperators(t *testing.T) {
+	data := map[string]interface{}{
+		"CheckSecurity": true,
+		"Level":         "CheckEverything",
+		"Length":        12,
+		"Roles":         []interface{}{"Administrator", "User"},
+	}
+	tests := []struct {
+		assertion yamlAssertion
+		allow     bool
+	}{
+		{assertion: yamlAssertion{Path: "$.CheckSecurity", Operator: assertEquals, Value: true}, allow: true},
+		{assertion: yamlAssertion{Path: "$.CheckSecurity", Operator: assertNotEquals, Value: true}, allow: false},
+		{assertion: yamlAssertion{Path: "$.Length", Operator: assertEquals, Value: 12.0}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Level", Operator: assertMatches, Value: "^Check"}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Level", Operator: assertNotMatches, Value: "^Check"}, allow: false},
+		{assertion: yamlAssertion{Path: "$.Level", Operator: assertIn, Value: []interface{}{"CheckEverything", "Prototype"}}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Roles[*]", Operator: assertNotIn, Value: []interface{}{"Guest"}}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Length", Operator: assertLessThan, Value: 12}, allow: false},
+		{assertion: yamlAssertion{Path: "$.Length", Operator: assertLessOrEqual, Value: 12}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Length", Operator: assertGreaterThan, Value: 8}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Level", Operator: assertGreaterOrEqual, Value: 8}, allow: false},
+		{assertion: yamlAssertion{Path: "$.Missing", Operator: assertExists}, allow: false},
+		{assertion: yamlAssertion{Path: "$.Missing", Operator: assertNotExists}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Missing", Operator: assertEquals, Value: true}, allow: true},
+		{assertion: yamlAssertion{Path: "$.Roles[*]", Operator: assertMinCount, Value: 3}, allow: false},
+		{assertion: yamlAssertion{Path: "$.Roles[*]", Operator: assertMaxCount, Value: 2}, allow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.assertion.Path+" "+tt.assertion.Operator, func(t *testing.T) {
+			compiled, err := compileYamlAssertion(tt.assertion, nil)
+			if err != nil {
+				t.Fatalf("Failed to compile assertion: %v", err)
+			}
+			rule := &compiledYamlRule{assertions: []compiledYamlAssertion{compiled}}
+			if allow, errors := rule.evaluate(data); allow != tt.allow {
+				t.Fatalf("expected allow=%v, got %v (errors: %v)", tt.allow, allow, errors)
+			}
+		})
+	}
+}
+
+func TestCompileYamlAssertion_Invalid(t *testing.T) {
+	tests := []yamlAssertion{
+		{Path: "$.Name", Operator: "startsWith", Value: "A"},
+		{Path: "$.Name", Operator: assertMatches, Value: "("},
+		{Path: "$.Name", Operator: assertIn, Value: "A"},
+		{Path: "$.Name", Operator: assertLessThan, Value: "ten"},
+		{Path: "$.Name", Operator: assertEquals, Setting: "undeclared"},
+	}
+	for _, assertion := range tests {
+		if _, err := compileYamlAssertion(assertion, map[string]interface{}{}); err == nil {
+			t.Errorf("expected %+v to be rejected", assertion)
+		}
+	}
+}
+
+func TestEvalTestcase_Yaml(t *testing.T) {
+	rulePath := writeTestRule(t, "002_0101_entities.yaml", testYamlRule)
+	rule, err := parseRuleMetadata_Yaml(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to parse rule metadata: %v", err)
+	}
+	if rule.Language != LanguageYaml || rule.RuleNumber != "002_0101" || rule.Settings["maxEntities"] != 2 {
+		t.Fatalf("unexpected rule metadata: %+v", rule)
+	}
+
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "DomainModel.yaml", "Entities:\n  - Name: Customer\n    Attributes:\n      - Name: Code\n      - Name: name\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	testcase, err := evalTestcase_Yaml(rulePath, filepath.Join(modelDir, "DomainModel.yaml"), rule.RuleNumber, false, modelDir, ruleSettings(*rule))
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testcase.Failure == nil || len(testcase.Violations) != 1 {
+		t.Fatalf("expected one violation, got %+v", testcase)
+	}
+	violation := testcase.Violations[0]
+	if violation.Message != "Attribute name must start with an uppercase letter" || violation.Path != "/Entities/0/Attributes/1/Name" || violation.Line != 5 {
+		t.Fatalf("unexpected violation: %+v", violation)
+	}
+
+	tooMany, err := evalTestcase_Yaml(rulePath, filepath.Join(modelDir, "DomainModel.yaml"), rule.RuleNumber, false, modelDir, map[string]interface{}{"maxEntities": 0.0})
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if len(tooMany.Violations) != 2 || tooMany.Violations[0].Message != "$.Entities[*] maxCount 0, got 1" {
+		t.Fatalf("expected the configured maxEntities to apply, got %+v", tooMany.Violations)
+	}
+}
+
+func TestReadRulesMetadata_YamlRules(t *testing.T) {
+	rulesDir := t.TempDir()
+	files := map[string]string{
+		"002_0101_entities.yaml":      testYamlRule,
+		"002_0101_entities_test.yaml": "TestCases:\n- name: allow\n  input:\n    Entities: []\n  allow: true\n- name: no_allow\n  input:\n    Entities:\n      - Attributes:\n          - Name: code\n  allow: false\n",
+		"ruleset.yaml":                "name: not a rule\n",
+		"modules.yaml":                "- assertions\n",
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+
+	rules, err := ReadRulesMetadata(rulesDir)
+	if err != nil {
+		t.Fatalf("Failed to read rules metadata: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Language != LanguageYaml {
+		t.Fatalf("expected only the YAML rule, got %+v", rules)
+	}
+	if err := TestAll(rulesDir); err != nil {
+		t.Fatalf("Expected rule tests to pass: %v", err)
+	}
+
+	if err := writeTestFile(rulesDir, "002_0102_typo.yaml", "metadata:\n  title: Typo\n  description: Typo\n  custom:\n    rulenumber: \"002_0102\"\nassertions:\n  - path: $.Name\n    operater: exists\n"); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	if _, err := ReadRulesMetadata(rulesDir); err == nil || !strings.Contains(err.Error(), "002_0102_typo.yaml") {
+		t.Fatalf("expected unknown assertion fields to be rejected, got: %v", err)
+	}
+
+	// A rule with a syntax error is reported rather than skipped.
+	if err := os.Remove(filepath.Join(rulesDir, "002_0102_typo.yaml")); err != nil {
+		t.Fatalf("Failed to remove rule file: %v", err)
+	}
+	if err := writeTestFile(rulesDir, "002_0103_broken.yaml", "metadata:\n  title: Broken\nassertions:\n  - path: [$.Name\n"); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	if _, err := ReadRulesMetadata(rulesDir); err == nil || !strings.Contains(err.Error(), "002_0103_broken.yaml") {
+		t.Fatalf("expected the syntax error to be reported, got: %v", err)
+	}
+	validation, err := ValidateRules(rulesDir, "")
+	if err != nil {
+		t.Fatalf("Failed to validate rules: %v", err)
+	}
+	if validation.Errors != 1 || filepath.Base(validation.Findings[0].Path) != "002_0103_broken.yaml" {
+		t.Fatalf("expected rules validate to report the broken rule, got %+v", validation.Findings)
+	}
+}
@@ -0,0 +1,240 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/open-policy-agent/opa/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// modelIndex lists the files of a modelsource. It is built with a single walk
+// per lint run and shared by every rule.
+type modelIndex struct {
+	root      string
+	documents []*modelDocument
+	byRelPath map[string]*modelDocument
+}
+
+// modelDocument is one file of the modelsource. Its content is read, hashed
+// and parsed at most once while rules hold a reference to it; the parsed value
+// is shared read-only by those rules and dropped after the last one releases it.
+type modelDocument struct {
+	path    string
+	relPath string
+
+	refs atomic.Int32
+
+	mu        sync.Mutex
+	loaded    bool
+	parsed    bool
+	content   []byte
+	hash      string
+	data      map[string]interface{}
+	node      *yaml.Node
+	regoInput ast.Value
+	err       error
+}
+
+func newModelDocument(path string, relPath string) *modelDocument {
+	return &modelDocument{path: path, relPath: relPath}
+}
+
+// buildModelIndex walks modelSourcePath once. Documents are kept in walk
+// order, which is the order testcases are reported in.
+func buildModelIndex(modelSourcePath string) (*modelIndex, error) {
+	index := &modelIndex{
+		root:      modelSourcePath,
+		byRelPath: map[string]*modelDocument{},
+	}
+	err := filepath.Walk(modelSourcePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Skip directories
+		if info.IsDir() {
+			return nil
+		}
+		// Get relative path from working directory
+		relPath, err := filepath.Rel(modelSourcePath, path)
+		if err != nil {
+			return err
+		}
+		document := newModelDocument(path, relPath)
+		index.documents = append(index.documents, document)
+		index.byRelPath[filepath.ToSlash(relPath)] = document
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// document returns the document at the given slash-separated path relative
+// to the modelsource, or nil.
+func (i *modelIndex) document(relPath string) *modelDocument {
+	return i.byRelPath[relPath]
+}
+
+// rulePatternRegexp converts the custom.input pattern of a rule into the
+// regular expression matched against modelsource-relative paths.
+func rulePatternRegexp(pattern string) (*regexp.Regexp, error) {
+	// backwards compatible with old filepath.glob(...)
+	if !strings.HasPrefix(pattern, ".*") {
+		oldPattern := pattern
+		pattern = strings.ReplaceAll(pattern, "$", "\\$")
+		pattern = strings.ReplaceAll(pattern, ".", "\\.")
+		pattern = strings.ReplaceAll(pattern, "**", ".*")
+		log.Infof("Expanded old pattern: %v -> %v", oldPattern, pattern)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input pattern %v: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// match returns the documents whose relative path matches a rule pattern.
+func (i *modelIndex) match(pattern string) ([]*modelDocument, error) {
+	re, err := rulePatternRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]*modelDocument, 0)
+	for _, document := range i.documents {
+		if re.MatchString(document.relPath) {
+			matches = append(matches, document)
+		}
+	}
+	if len(matches) == 0 {
+		log.Warnf("No matches found for pattern %v ", re)
+	}
+	return matches, nil
+}
+
+// matches reports whether any document path matches re.
+func (i *modelIndex) matches(re *regexp.Regexp) bool {
+	for _, document := range i.documents {
+		if re.MatchString(document.relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *modelDocument) retain() {
+	d.refs.Add(1)
+}
+
+// release drops the parsed document once no rule holds a reference to it.
+func (d *modelDocument) release() {
+	if d.refs.Add(-1) > 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.loaded = false
+	d.parsed = false
+	d.content = nil
+	d.data = nil
+	d.node = nil
+	d.regoInput = nil
+	d.err = nil
+}
+
+func (d *modelDocument) loadLocked() error {
+	if d.loaded {
+		return d.err
+	}
+	d.loaded = true
+	d.content, d.err = os.ReadFile(d.path)
+	if d.err == nil {
+		d.hash = hashRuleContent(d.content)
+	}
+	return d.err
+}
+
+// contentHash returns the SHA256 of the document, used in cache keys.
+func (d *modelDocument) contentHash() (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.loadLocked(); err != nil {
+		return "", err
+	}
+	return d.hash, nil
+}
+
+// yamlDocument returns the decoded document and the parsed node used to
+// resolve violation locations. Callers must not modify either.
+func (d *modelDocument) yamlDocument() (map[string]interface{}, *yaml.Node, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.loadLocked(); err != nil {
+		return nil, nil, err
+	}
+	if !d.parsed {
+		d.parsed = true
+		var node yaml.Node
+		if err := yaml.Unmarshal(d.content, &node); err != nil {
+			d.err = err
+		} else if err := node.Decode(&d.data); err != nil {
+			d.err = err
+		} else {
+			d.node = &node
+		}
+		d.content = nil
+	}
+	return d.data, d.node, d.err
+}
+
+// regoValue returns the document converted to a Rego value, so that every
+// Rego rule can evaluate it without converting the input again.
+func (d *modelDocument) regoValue() (ast.Value, error) {
+	data, _, err := d.yamlDocument()
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.regoInput == nil {
+		value, err := ast.InterfaceToValue(data)
+		if err != nil {
+			return nil, err
+		}
+		d.regoInput = value
+	}
+	return d.regoInput, nil
+}
+
+// cloneDocumentValue deep-copies a decoded YAML value. JavaScript rules get
+// a copy because sobek exposes Go maps and slices by reference.
+func cloneDocumentValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneDocumentValue(item)
+		}
+		return clone
+	case map[interface{}]interface{}:
+		clone := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			clone[key] = cloneDocumentValue(item)
+		}
+		return clone
+	case []interface{}:
+		clone := make([]interface{}, len(v))
+		for i, item := range v {
+			clone[i] = cloneDocumentValue(item)
+		}
+		return clone
+	default:
+		return v
+	}
+}
@@ -0,0 +1,391 @@
+package lint
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTypesFile is the file written by the types command when no output is
+// given.
+const DefaultTypesFile = "mxlint.d.ts"
+
+// typeShape is the union of the values observed at one place of the model:
+// primitives, elements by $Type, objects without $Type and arrays.
+type typeShape struct {
+	primitives   map[string]bool
+	elementTypes map[string]bool
+	object       *objectShape
+	array        *typeShape
+}
+
+// objectShape merges the objects observed at one place of the model, or all
+// elements of one $Type. A property is optional when it is missing from some
+// of the objects.
+type objectShape struct {
+	count      int
+	properties map[string]*propertyShape
+}
+
+type propertyShape struct {
+	count int
+	shape *typeShape
+}
+
+func newTypeShape() *typeShape {
+	return &typeShape{primitives: map[string]bool{}, elementTypes: map[string]bool{}}
+}
+
+func newObjectShape() *objectShape {
+	return &objectShape{properties: map[string]*propertyShape{}}
+}
+
+// modelTypes is inferred from the documents of a modelsource.
+type modelTypes struct {
+	elements map[string]*objectShape
+	// documents are the interfaces of document roots: the $Type of the root,
+	// or the file name for documents without one, such as Metadata.yaml.
+	documents map[string]bool
+}
+
+func newModelTypes() *modelTypes {
+	return &modelTypes{elements: map[string]*objectShape{}, documents: map[string]bool{}}
+}
+
+// observeDocument merges the shape of a document into the model types.
+func (m *modelTypes) observeDocument(relPath string, document map[string]interface{}) {
+	name, ok := document["$Type"].(string)
+	if !ok || name == "" {
+		name = strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+	}
+	m.documents[name] = true
+	m.observeElement(name, document)
+}
+
+func (m *modelTypes) observeElement(name string, value map[string]interface{}) {
+	shape, ok := m.elements[name]
+	if !ok {
+		shape = newObjectShape()
+		m.elements[name] = shape
+	}
+	m.observeObject(shape, value)
+}
+
+func (m *modelTypes) observeObject(shape *objectShape, value map[string]interface{}) {
+	shape.count++
+	for key, item := range value {
+		property, ok := shape.properties[key]
+		if !ok {
+			property = &propertyShape{shape: newTypeShape()}
+			shape.properties[key] = property
+		}
+		property.count++
+		m.observe(property.shape, item)
+	}
+}
+
+func (m *modelTypes) observe(shape *typeShape, value interface{}) {
+	switch typed := value.(type) {
+	case nil:
+		shape.primitives["null"] = true
+	case string:
+		shape.primitives["string"] = true
+	case bool:
+		shape.primitives["boolean"] = true
+	case int, int64, uint64, float64:
+		shape.primitives["number"] = true
+	case []interface{}:
+		if shape.array == nil {
+			shape.array = newTypeShape()
+		}
+		for _, item := range typed {
+			m.observe(shape.array, item)
+		}
+	case map[string]interface{}:
+		if name, ok := typed["$Type"].(string); ok && name != "" {
+			shape.elementTypes[name] = true
+			m.observeElement(name, typed)
+			return
+		}
+		if shape.object == nil {
+			shape.object = newObjectShape()
+		}
+		m.observeObject(shape.object, typed)
+	case map[interface{}]interface{}:
+		m.observe(shape, convertToStringKeyMap(typed))
+	default:
+		shape.primitives["unknown"] = true
+	}
+}
+
+// inferModelTypes reads every YAML document of the modelsource.
+func inferModelTypes(modelSourcePath string) (*modelTypes, int, error) {
+	index, err := buildModelIndex(modelSourcePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	types := newModelTypes()
+	count := 0
+	for _, document := range index.documents {
+		if !strings.HasSuffix(document.path, ".yaml") {
+			continue
+		}
+		content, err := os.ReadFile(document.path)
+		if err != nil {
+			return nil, 0, err
+		}
+		var data map[string]interface{}
+		if err := yaml.Unmarshal(content, &data); err != nil {
+			log.Warnf("Skipping %s: %s", document.relPath, err)
+			continue
+		}
+		if data == nil {
+			continue
+		}
+		types.observeDocument(document.relPath, data)
+		count++
+	}
+	return types, count, nil
+}
+
+var typeIdentifierInvalid = regexp.MustCompile(`[^A-Za-z0-9_$]`)
+
+var propertyIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeIdentifier turns a $Type or file name into a TypeScript identifier,
+// e.g. Microflows$Microflow stays as it is.
+func typeIdentifier(name string) string {
+	identifier := typeIdentifierInvalid.ReplaceAllString(name, "_")
+	if identifier == "" || (identifier[0] >= '0' && identifier[0] <= '9') {
+		identifier = "_" + identifier
+	}
+	return identifier
+}
+
+func propertyName(name string) string {
+	if propertyIdentifier.MatchString(name) {
+		return name
+	}
+	quoted, _ := json.Marshal(name)
+	return string(quoted)
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// expression returns the TypeScript type of a shape. Nested objects without
+// $Type are written inline at the given indentation.
+func (s *typeShape) expression(indent string) string {
+	parts := make([]string, 0)
+	for _, name := range sortedKeys(s.elementTypes) {
+		parts = append(parts, typeIdentifier(name))
+	}
+	if s.object != nil {
+		parts = append(parts, s.object.literal(indent))
+	}
+	if s.array != nil {
+		element := s.array.expression(indent)
+		if strings.Contains(element, " | ") {
+			element = "(" + element + ")"
+		}
+		parts = append(parts, element+"[]")
+	}
+	for _, primitive := range []string{"string", "number", "boolean", "unknown", "null"} {
+		if s.primitives[primitive] {
+			parts = append(parts, primitive)
+		}
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (o *objectShape) literal(indent string) string {
+	if len(o.properties) == 0 {
+		return "Record<string, unknown>"
+	}
+	var builder strings.Builder
+	builder.WriteString("{\n")
+	o.writeProperties(&builder, indent+"    ", "")
+	builder.WriteString(indent + "}")
+	return builder.String()
+}
+
+// writeProperties writes one line per property. typeName is the $Type of an
+// element interface, whose $Type property is written as a literal.
+func (o *objectShape) writeProperties(w io.StringWriter, indent string, typeName string) {
+	for _, key := range sortedKeys(o.properties) {
+		property := o.properties[key]
+		optional := ""
+		if property.count < o.count {
+			optional = "?"
+		}
+		expression := property.shape.expression(indent)
+		if key == "$Type" && typeName != "" {
+			quoted, _ := json.Marshal(typeName)
+			expression = string(quoted)
+		}
+		w.WriteString(fmt.Sprintf("%s%s%s: %s;\n", indent, propertyName(key), optional, expression))
+	}
+}
+
+// writeDeclarations writes the model interfaces followed by the rule API.
+func (m *modelTypes) writeDeclarations(w io.Writer, source string) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "// Generated by mxlint-cli types from %s. Do not edit; run the command again after the model changes.\n", filepath.Base(source))
+	fmt.Fprintf(out, "// Reference it from a rule with: /// <reference path=\"%s\" />\n", DefaultTypesFile)
+
+	for _, name := range sortedKeys(m.elements) {
+		shape := m.elements[name]
+		fmt.Fprintf(out, "\ninterface %s {\n", typeIdentifier(name))
+		typeName := ""
+		if _, ok := shape.properties["$Type"]; ok {
+			typeName = name
+		}
+		shape.writeProperties(out, "    ", typeName)
+		out.WriteString("}\n")
+	}
+
+	elements := make([]string, 0)
+	documents := make([]string, 0)
+	for _, name := range sortedKeys(m.elements) {
+		if _, ok := m.elements[name].properties["$Type"]; ok {
+			elements = append(elements, typeIdentifier(name))
+		}
+		if m.documents[name] {
+			documents = append(documents, typeIdentifier(name))
+		}
+	}
+	fmt.Fprintf(out, "\n/** Any model element with a $Type. */\ntype MendixElement = %s;\n", typeUnion(elements))
+	fmt.Fprintf(out, "\n/** Any document of the modelsource, as passed to rule(input). */\ntype MendixDocument = %s;\n", typeUnion(documents))
+
+	metadata := "Record<string, unknown>"
+	if _, ok := m.elements["Metadata"]; ok && m.documents["Metadata"] {
+		metadata = "Metadata"
+	}
+	fmt.Fprintf(out, ruleDeclarations, metadata)
+	return out.Flush()
+}
+
+func typeUnion(names []string) string {
+	if len(names) == 0 {
+		return "Record<string, unknown>"
+	}
+	return strings.Join(names, " | ")
+}
+
+// ruleDeclarations declares the contract between mxlint and JavaScript and
+// TypeScript rules. %s is the type of Metadata.yaml.
+const ruleDeclarations = `
+/** Severity of a rule or a violation. */
+type RuleSeverity = "LOW" | "MEDIUM" | "HIGH";
+
+/** Settings of a rule: the custom.settings defaults merged with rules.settings. */
+type RuleSettings = Record<string, unknown>;
+
+/** The metadata constant declared by every rule. */
+interface RuleMetadata {
+    title: string;
+    description: string;
+    custom: {
+        rulenumber: string;
+        category?: string;
+        rulename?: string;
+        severity?: RuleSeverity;
+        remediation?: string;
+        /** Regular expression on the document path relative to the modelsource. */
+        input?: string;
+        scope?: "document" | "project";
+        settings?: RuleSettings;
+    };
+    [key: string]: unknown;
+}
+
+/** A violation that points at the offending element. */
+interface RuleViolation {
+    message: string;
+    /** JSON pointer into the document, e.g. /Entities/3/Name. */
+    path?: string;
+    /** Defaults to the severity of the rule. */
+    severity?: RuleSeverity;
+    /** Defaults to the Name at path. */
+    element?: string;
+    /** Path of the document the violation belongs to, for project-scoped rules. */
+    document?: string;
+}
+
+/** The value returned by rule(input, settings). */
+interface RuleResult {
+    allow: boolean;
+    errors: (string | RuleViolation)[];
+}
+
+/** The input of a rule with custom.scope: project. */
+interface ProjectInput {
+    /** Every document matching custom.input, keyed by path. */
+    documents: Record<string, MendixDocument>;
+    metadata: %s | null;
+}
+
+/** The rule function declared by every rule, e.g. const check: RuleFunction<Microflows$Microflow> = rule. */
+type RuleFunction<T = MendixDocument> = (input: T, settings: RuleSettings) => RuleResult;
+
+/** Utilities available to rules. Paths are relative to the modelsource and must stay within it. */
+declare const mxlint: {
+    io: {
+        readfile(path: string): string;
+        readYaml<T = MendixDocument>(path: string): T;
+        readJson<T = unknown>(path: string): T;
+        listdir(path: string): string[];
+        isdir(path: string): boolean;
+    };
+    /** The external data files of the rules directory and rules.data, by file name. */
+    data: Record<string, unknown>;
+};
+`
+
+// GenerateTypes infers a TypeScript interface per $Type from the documents of
+// the modelsource and writes them, with the declarations of the rule API, to
+// outputPath. It returns the number of documents read and interfaces written.
+func GenerateTypes(modelSourcePath string, outputPath string) (int, int, error) {
+	if _, err := os.Stat(modelSourcePath); err != nil {
+		return 0, 0, fmt.Errorf("failed to read modelsource %s: %w", modelSourcePath, err)
+	}
+	types, count, err := inferModelTypes(modelSourcePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	if count == 0 {
+		return 0, 0, fmt.Errorf("no documents found in %s; run export first", modelSourcePath)
+	}
+
+	if dir := filepath.Dir(outputPath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return 0, 0, err
+		}
+	}
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+	if err := types.writeDeclarations(file, modelSourcePath); err != nil {
+		return 0, 0, err
+	}
+	return count, len(types.elements), file.Close()
+}
@@ -0,0 +1,102 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evanw/esbuild/pkg/api"
+)
+
+func TestGenerateTypes(t *testing.T) {
+	modelDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(modelDir, "MyFirstModule"), 0755); err != nil {
+		t.Fatalf("Failed to create module directory: %v", err)
+	}
+	documents := map[string]string{
+		"MyFirstModule/First.Microflows$Microflow.yaml": `$Type: Microflows$Microflow
+Name: First
+Documentation: ""
+ObjectCollection:
+  $Type: Microflows$MicroflowObjectCollection
+  Objects:
+    - $Type: Microflows$StartEvent
+    - $Type: Microflows$ActionActivity
+      Action:
+        $Type: Microflows$CreateObjectAction
+        Entity: MyFirstModule.Customer
+`,
+		"MyFirstModule/Second.Microflows$Microflow.yaml": `$Type: Microflows$Microflow
+Name: Second
+ObjectCollection:
+  $Type: Microflows$MicroflowObjectCollection
+  Objects: []
+ReturnVariableName: null
+`,
+		"Metadata.yaml": `ProductVersion: 10.6.0
+Modules:
+  - Name: MyFirstModule
+    "Display name": First
+  - Name: Administration
+    AppStoreVersion: 2.0.0
+`,
+	}
+	for name, content := range documents {
+		if err := writeTestFile(modelDir, name, content); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	rulesDir := t.TempDir()
+	output := filepath.Join(rulesDir, DefaultTypesFile)
+	count, interfaces, err := GenerateTypes(modelDir, output)
+	if err != nil {
+		t.Fatalf("Failed to generate types: %v", err)
+	}
+	if count != 3 || interfaces != 6 {
+		t.Fatalf("expected 6 interfaces from 3 documents, got %d from %d", interfaces, count)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read types: %v", err)
+	}
+	declarations := string(content)
+
+	expected := []string{
+		"interface Microflows$Microflow {\n    $Type: \"Microflows$Microflow\";\n    Documentation?: string;\n    Name: string;\n    ObjectCollection: Microflows$MicroflowObjectCollection;\n    ReturnVariableName?: null;\n}",
+		"    Objects: (Microflows$ActionActivity | Microflows$StartEvent)[];",
+		"    Action: Microflows$CreateObjectAction;",
+		"interface Metadata {\n    Modules: {\n        AppStoreVersion?: string;\n        \"Display name\"?: string;\n        Name: string;\n    }[];\n    ProductVersion: string;\n}",
+		"type MendixDocument = Metadata | Microflows$Microflow;",
+		"    metadata: Metadata | null;",
+		"declare const mxlint: {",
+		"interface RuleMetadata {",
+		"type RuleFunction<T = MendixDocument>",
+	}
+	for _, part := range expected {
+		if !strings.Contains(declarations, part) {
+			t.Fatalf("expected declarations to contain:\n%s\n\ngot:\n%s", part, declarations)
+		}
+	}
+
+	result := api.Transform(declarations, api.TransformOptions{Loader: api.LoaderTS})
+	if len(result.Errors) > 0 {
+		t.Fatalf("expected valid TypeScript declarations: %s", formatEsbuildErrors(result.Errors))
+	}
+
+	// The declarations can live next to the rules without being read as a rule.
+	rules, err := ReadRulesMetadata(rulesDir)
+	if err != nil {
+		t.Fatalf("Failed to read rules: %v", err)
+	}
+	if len(rules) != 0 {
+		t.Fatalf("expected no rules, got %+v", rules)
+	}
+}
+
+func TestGenerateTypes_EmptyModelsource(t *testing.T) {
+	if _, _, err := GenerateTypes(t.TempDir(), filepath.Join(t.TempDir(), DefaultTypesFile)); err == nil {
+		t.Fatal("expected an empty modelsource to be rejected")
+	}
+}
@@ -0,0 +1,432 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NewRuleOptions describes the rule generated by NewRule.
+type NewRuleOptions struct {
+	RulesPath       string
+	ModelSourcePath string
+	// Language is rego, javascript or typescript; js and ts are accepted too.
+	Language    string
+	Category    string
+	Input       string
+	Title       string
+	Description string
+	Severity    string
+	Remediation string
+}
+
+var ruleNumberFormat = regexp.MustCompile(`^(\d{3})_(\d{4})$`)
+
+var identifierSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ruleFileExtensions maps the languages new-rule generates to file extensions.
+var ruleFileExtensions = map[string]string{
+	LanguageRego:       ".rego",
+	LanguageJavascript: ".js",
+	LanguageTypescript: ".ts",
+}
+
+func normalizeRuleLanguage(language string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(language)) {
+	case "rego":
+		return LanguageRego, nil
+	case "js", "javascript":
+		return LanguageJavascript, nil
+	case "ts", "typescript":
+		return LanguageTypescript, nil
+	}
+	return "", fmt.Errorf("unsupported language %q; use rego, js or ts", language)
+}
+
+// nextRuleNumber returns the next free rule number of a category and the
+// directory of the rules with that prefix. The prefix is the one most rules of
+// the category use; a new category gets the prefix after the highest one.
+func nextRuleNumber(rules []Rule, category string, rulesPath string) (string, string, error) {
+	prefixCounts := map[int]int{}
+	highest := map[int]int{}
+	directories := map[int]string{}
+	highestPrefix := 0
+	for _, rule := range rules {
+		parts := ruleNumberFormat.FindStringSubmatch(rule.RuleNumber)
+		if parts == nil {
+			continue
+		}
+		prefix, _ := strconv.Atoi(parts[1])
+		number, _ := strconv.Atoi(parts[2])
+		if strings.EqualFold(rule.Category, category) {
+			prefixCounts[prefix]++
+		}
+		if _, ok := highest[prefix]; !ok || number > highest[prefix] {
+			highest[prefix] = number
+			directories[prefix] = filepath.Dir(rule.Path)
+		}
+		highestPrefix = max(highestPrefix, prefix)
+	}
+
+	prefix := 0
+	found := false
+	for candidate, count := range prefixCounts {
+		if !found || count > prefixCounts[prefix] || (count == prefixCounts[prefix] && candidate < prefix) {
+			prefix = candidate
+			found = true
+		}
+	}
+	if !found {
+		prefix = highestPrefix + 1
+		if prefix > 999 {
+			return "", "", fmt.Errorf("no rule number prefix left for category %s", category)
+		}
+		return fmt.Sprintf("%03d_0001", prefix), rulesPath, nil
+	}
+	if highest[prefix] >= 9999 {
+		return "", "", fmt.Errorf("no rule number left in prefix %03d of category %s", prefix, category)
+	}
+	return fmt.Sprintf("%03d_%04d", prefix, highest[prefix]+1), directories[prefix], nil
+}
+
+// snakeCase turns a title into a file and package name, e.g. "No demo
+// users" becomes no_demo_users.
+func snakeCase(value string) string {
+	return strings.Trim(identifierSeparator.ReplaceAllString(strings.ToLower(value), "_"), "_")
+}
+
+// pascalCase turns a title into a rulename, e.g. NoDemoUsers.
+func pascalCase(value string) string {
+	var builder strings.Builder
+	for _, word := range strings.Split(snakeCase(value), "_") {
+		if word != "" {
+			builder.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return builder.String()
+}
+
+// ruleSample is the document the generated rule and test cases are built
+// from, with the property the skeleton rule checks.
+type ruleSample struct {
+	relPath  string
+	document *yaml.Node
+	property string
+}
+
+// findRuleSample returns the first document of the modelsource matching the
+// input pattern that has a non-empty string property to check, preferring
+// Name. Without one, a placeholder document is returned.
+func findRuleSample(modelSourcePath string, pattern string) (*ruleSample, error) {
+	if _, err := rulePatternRegexp(pattern); err != nil {
+		return nil, fmt.Errorf("invalid input pattern %q: %w", pattern, err)
+	}
+	if modelSourcePath != "" {
+		if _, err := os.Stat(modelSourcePath); err == nil {
+			index, err := buildModelIndex(modelSourcePath)
+			if err != nil {
+				return nil, err
+			}
+			documents, err := index.match(pattern)
+			if err != nil {
+				return nil, err
+			}
+			sort.Slice(documents, func(i, j int) bool {
+				return documents[i].relPath < documents[j].relPath
+			})
+			for _, document := range documents {
+				if !strings.HasSuffix(document.path, ".yaml") {
+					continue
+				}
+				_, node, err := readYAMLNodeFromPath(document.path)
+				if err != nil || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+					continue
+				}
+				if property := sampleProperty(node.Content[0]); property != "" {
+					return &ruleSample{relPath: filepath.ToSlash(document.relPath), document: node.Content[0], property: property}, nil
+				}
+			}
+		}
+	}
+
+	log.Warnf("No document in %s matches %s; the test cases use a placeholder document", modelSourcePath, pattern)
+	placeholder := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "Name"},
+		{Kind: yaml.ScalarNode, Value: "Example"},
+	}}
+	return &ruleSample{document: placeholder, property: "Name"}, nil
+}
+
+func sampleProperty(mapping *yaml.Node) string {
+	candidate := ""
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i].Value, mapping.Content[i+1]
+		if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!str" || value.Value == "" {
+			continue
+		}
+		if key == "Name" {
+			return key
+		}
+		if candidate == "" && !strings.HasPrefix(key, "$") && key != documentationKey {
+			candidate = key
+		}
+	}
+	return candidate
+}
+
+func quoted(value string) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+// yamlScalar formats a value for the METADATA block of a Rego rule.
+func yamlScalar(value string) string {
+	encoded, _ := yaml.Marshal(value)
+	return strings.TrimSuffix(string(encoded), "\n")
+}
+
+type newRuleTemplate struct {
+	NewRuleOptions
+	RuleNumber  string
+	RuleName    string
+	PackageName string
+	Property    string
+}
+
+func (t newRuleTemplate) message() string {
+	return t.Property + " must be set"
+}
+
+func (t newRuleTemplate) rego() string {
+	var b strings.Builder
+	b.WriteString("# METADATA\n# scope: package\n")
+	fmt.Fprintf(&b, "# title: %s\n", yamlScalar(t.Title))
+	fmt.Fprintf(&b, "# description: %s\n", yamlScalar(t.Description))
+	b.WriteString("# custom:\n")
+	fmt.Fprintf(&b, "#  category: %s\n", yamlScalar(t.Category))
+	fmt.Fprintf(&b, "#  rulename: %s\n", t.RuleName)
+	fmt.Fprintf(&b, "#  severity: %s\n", t.Severity)
+	fmt.Fprintf(&b, "#  rulenumber: %q\n", t.RuleNumber)
+	fmt.Fprintf(&b, "#  remediation: %s\n", yamlScalar(t.Remediation))
+	fmt.Fprintf(&b, "#  input: %s\n", yamlScalar(t.Input))
+	fmt.Fprintf(&b, `package %s
+
+import rego.v1
+
+annotation := rego.metadata.chain()[1].annotations
+
+default allow := false
+
+allow if count(errors) == 0
+
+# Replace this check with the rule.
+errors contains error if {
+    object.get(input, %s, "") == ""
+    error := sprintf("[%%v, %%v, %%v] %%v",
+        [
+            annotation.custom.severity,
+            annotation.custom.category,
+            annotation.custom.rulenumber,
+            %s,
+        ]
+    )
+}
+`, t.PackageName, quoted(t.Property), quoted(t.message()))
+	return b.String()
+}
+
+func (t newRuleTemplate) javascript(typescript bool) string {
+	inputType, errorsType := "", ""
+	if typescript {
+		inputType, errorsType = ": Record<string, unknown>", ": string[]"
+	}
+	property := "input." + t.Property
+	if !propertyIdentifier.MatchString(t.Property) {
+		property = "input[" + quoted(t.Property) + "]"
+	}
+	return fmt.Sprintf(`const metadata = {
+    scope: "package",
+    title: %s,
+    description: %s,
+    custom: {
+        category: %s,
+        rulename: %s,
+        severity: %s,
+        rulenumber: %s,
+        remediation: %s,
+        input: %s
+    }
+};
+
+function rule(input%s = {}) {
+    const errors%s = [];
+    const prefix = `+"`[${metadata.custom.severity}, ${metadata.custom.category}, ${metadata.custom.rulenumber}]`"+`;
+
+    // Replace this check with the rule.
+    if ((%s ?? "") === "") {
+        errors.push(prefix + " " + %s);
+    }
+
+    return {
+        allow: errors.length === 0,
+        errors
+    };
+}
+`, quoted(t.Title), quoted(t.Description), quoted(t.Category), quoted(t.RuleName), quoted(t.Severity),
+		quoted(t.RuleNumber), quoted(t.Remediation), quoted(t.Input), inputType, errorsType, property, quoted(t.message()))
+}
+
+// ruleTestCases builds a _test.yaml with a passing case on the sample and a
+// failing case on the sample without the checked property. The failing case
+// reuses the sample through a YAML merge key.
+func ruleTestCases(sample *ruleSample) ([]byte, error) {
+	scalar := func(value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	}
+	sample.document.Anchor = "sample"
+	inputKey := scalar("input")
+	if sample.relPath != "" {
+		inputKey.HeadComment = "Sample from " + sample.relPath
+	}
+	passing := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		scalar("name"), scalar("allow"),
+		scalar("allow"), {Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+		inputKey, sample.document,
+	}}
+	failing := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		scalar("name"), scalar("no_allow"),
+		scalar("allow"), {Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"},
+		scalar("errorCount"), {Kind: yaml.ScalarNode, Tag: "!!int", Value: "1"},
+		scalar("input"), {Kind: yaml.MappingNode, Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "<<"}, {Kind: yaml.AliasNode, Value: "sample", Alias: sample.document},
+			scalar(sample.property), {Kind: yaml.ScalarNode, Tag: "!!str", Value: "", Style: yaml.DoubleQuotedStyle},
+		}},
+	}}
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		scalar("TestCases"), {Kind: yaml.SequenceNode, Content: []*yaml.Node{passing, failing}},
+	}}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// NewRule writes a rule with complete metadata and a skeleton rule(), and its
+// _test.yaml, with the next free rule number of the category. The skeleton
+// checks that a property of a sample document from the modelsource is set,
+// so the generated test cases pass. It returns the generated rule.
+func NewRule(options NewRuleOptions) (*Rule, string, error) {
+	language, err := normalizeRuleLanguage(options.Language)
+	if err != nil {
+		return nil, "", err
+	}
+	if strings.TrimSpace(options.Category) == "" {
+		return nil, "", fmt.Errorf("a category is required")
+	}
+	options.Severity = normalizeSeverity(options.Severity)
+	if err := validateSeverity(options.Severity); err != nil {
+		return nil, "", err
+	}
+	if options.Description == "" {
+		options.Description = options.Title
+	}
+
+	if err := os.MkdirAll(options.RulesPath, 0755); err != nil {
+		return nil, "", err
+	}
+	rules, err := ReadRulesMetadata(options.RulesPath)
+	if err != nil {
+		return nil, "", err
+	}
+	ruleNumber, directory, err := nextRuleNumber(rules, options.Category, options.RulesPath)
+	if err != nil {
+		return nil, "", err
+	}
+	sample, err := findRuleSample(options.ModelSourcePath, options.Input)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := snakeCase(options.Title)
+	if name == "" {
+		name = "rule"
+	}
+	if strings.HasSuffix(name, "_test") {
+		name += "_rule"
+	}
+	template := newRuleTemplate{
+		NewRuleOptions: options,
+		RuleNumber:     ruleNumber,
+		RuleName:       pascalCase(options.Title),
+		PackageName:    "app.mendix." + regoPackageSegment(options.Category) + "." + regoPackageSegment(name),
+		Property:       sample.property,
+	}
+	var content string
+	switch language {
+	case LanguageRego:
+		content = template.rego()
+	case LanguageJavascript:
+		content = template.javascript(false)
+	case LanguageTypescript:
+		content = template.javascript(true)
+	}
+	testCases, err := ruleTestCases(sample)
+	if err != nil {
+		return nil, "", err
+	}
+
+	base := filepath.Join(directory, ruleNumber+"_"+name)
+	rulePath := base + ruleFileExtensions[language]
+	testPath := base + "_test.yaml"
+	for _, path := range []string{rulePath, testPath} {
+		if _, err := os.Stat(path); err == nil {
+			return nil, "", fmt.Errorf("%s already exists", path)
+		}
+	}
+	if err := os.WriteFile(rulePath, []byte(content), 0644); err != nil {
+		return nil, "", err
+	}
+	if err := os.WriteFile(testPath, testCases, 0644); err != nil {
+		return nil, "", err
+	}
+
+	rule, err := parseRuleMetadata(rulePath, language)
+	if err != nil {
+		return nil, "", fmt.Errorf("generated rule %s is invalid: %w", rulePath, err)
+	}
+	return rule, testPath, nil
+}
+
+// regoPackageSegment turns a name into a Rego package path segment.
+func regoPackageSegment(value string) string {
+	segment := snakeCase(value)
+	if segment == "" || (segment[0] >= '0' && segment[0] <= '9') {
+		segment = "rule_" + segment
+	}
+	return segment
+}
+
+func parseRuleMetadata(rulePath string, language string) (*Rule, error) {
+	switch language {
+	case LanguageRego:
+		return parseRuleMetadata_Rego(rulePath)
+	case LanguageJavascript:
+		return parseRuleMetadata_Javascript(rulePath)
+	}
+	return parseRuleMetadata_Typescript(rulePath)
+}
@@ -0,0 +1,130 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNextRuleNumber(t *testing.T) {
+	rules := []Rule{
+		{RuleNumber: "000_0004", Category: "Tooling", Path: "rules/000_tooling/000_0004_g.rego"},
+		{RuleNumber: "001_0002", Category: "Security", Path: "rules/001_security/001_0002_a.rego"},
+		{RuleNumber: "001_0007", Category: "Security", Path: "rules/001_security/001_0007_b.js"},
+		{RuleNumber: "002_0003", Category: "Security", Path: "rules/002_model/002_0003_c.rego"},
+		{RuleNumber: "002_0012", Category: "Naming", Path: "rules/002_model/002_0012_d.rego"},
+		{RuleNumber: "004_0001", Category: "Naming", Path: "rules/004_naming/004_0001_e.rego"},
+		{RuleNumber: "not-a-number", Category: "Naming", Path: "rules/f.rego"},
+	}
+	tests := []struct {
+		category  string
+		number    string
+		directory string
+	}{
+		{"Security", "001_0008", "rules/001_security"},
+		{"security", "001_0008", "rules/001_security"},
+		// Ties go to the lowest prefix, which continues after rules of other categories.
+		{"Naming", "002_0013", "rules/002_model"},
+		{"Performance", "005_0001", "rules"},
+		{"Tooling", "000_0005", "rules/000_tooling"},
+	}
+	for _, test := range tests {
+		number, directory, err := nextRuleNumber(rules, test.category, "rules")
+		if err != nil {
+			t.Fatalf("%s: %v", test.category, err)
+		}
+		if number != test.number || directory != test.directory {
+			t.Errorf("%s: expected %s in %s, got %s in %s", test.category, test.number, test.directory, number, directory)
+		}
+	}
+}
+
+func TestNewRule(t *testing.T) {
+	t.Cleanup(func() {
+		setRulesRoot("")
+	})
+	modelDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(modelDir, "MyFirstModule"), 0755); err != nil {
+		t.Fatalf("Failed to create module directory: %v", err)
+	}
+	microflow := "$Type: Microflows$Microflow\nDocumentation: \"\"\nName: ACT_Order_Create\nObjectCollection:\n  $Type: Microflows$MicroflowObjectCollection\n  Objects: []\n"
+	if err := writeTestFile(modelDir, "MyFirstModule/ACT_Order_Create.Microflows$Microflow.yaml", microflow); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	rulesDir := t.TempDir()
+	if err := writeTestFile(rulesDir, "001_0003_security_checks.rego", "# METADATA\n# title: Security\n# custom:\n#  category: Security\n#  rulenumber: 001_0003\n#  input: .*\\.yaml\npackage app.mendix.security\nimport rego.v1\ndefault allow := true\n"); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+
+	expected := map[string]string{
+		"rego": "001_0004_microflow_names_have_a_prefix.rego",
+		"js":   "001_0005_microflow_names_have_a_prefix_2.js",
+		"ts":   "001_0006_microflow_names_have_a_prefix_3.ts",
+	}
+	for i, language := range []string{"rego", "js", "ts"} {
+		title := "Microflow names have a prefix"
+		if i > 0 {
+			title += " " + string(rune('1'+i))
+		}
+		rule, testPath, err := NewRule(NewRuleOptions{
+			RulesPath:       rulesDir,
+			ModelSourcePath: modelDir,
+			Language:        language,
+			Category:        "Security",
+			Input:           `.*\$Microflow\.yaml`,
+			Title:           title,
+			Severity:        "high",
+		})
+		if err != nil {
+			t.Fatalf("%s: failed to generate rule: %v", language, err)
+		}
+		if filepath.Base(rule.Path) != expected[language] {
+			t.Fatalf("%s: expected %s, got %s", language, expected[language], rule.Path)
+		}
+		if rule.Category != "Security" || rule.Severity != "HIGH" || rule.Pattern != `.*\$Microflow\.yaml` || rule.RuleName == "" {
+			t.Fatalf("%s: unexpected metadata %+v", language, rule)
+		}
+		content, err := os.ReadFile(testPath)
+		if err != nil {
+			t.Fatalf("Failed to read test cases: %v", err)
+		}
+		if !strings.Contains(string(content), "# Sample from MyFirstModule/ACT_Order_Create.Microflows$Microflow.yaml") || !strings.Contains(string(content), "<<: *sample") {
+			t.Fatalf("%s: expected test cases from the sample document, got:\n%s", language, content)
+		}
+	}
+
+	if err := TestAll(rulesDir); err == nil || !strings.Contains(err.Error(), "1 rule test(s) failed") {
+		t.Fatalf("expected only the existing rule without test cases to fail, got %v", err)
+	}
+	rules, err := ReadRulesMetadata(rulesDir)
+	if err != nil {
+		t.Fatalf("Failed to read rules: %v", err)
+	}
+	for _, rule := range rules {
+		if rule.RuleNumber == "001_0003" {
+			continue
+		}
+		if testsuite, err := runTestCases(rule, 0); err != nil || testsuite.Tests != 2 {
+			t.Fatalf("%s: expected the generated test cases to pass, got %v", filepath.Base(rule.Path), err)
+		}
+	}
+}
+
+func TestNewRule_Invalid(t *testing.T) {
+	rulesDir := t.TempDir()
+	invalid := map[string]NewRuleOptions{
+		"language": {RulesPath: rulesDir, Language: "python", Category: "Security", Input: ".*", Title: "A", Severity: "LOW"},
+		"category": {RulesPath: rulesDir, Language: "rego", Input: ".*", Title: "A", Severity: "LOW"},
+		"severity": {RulesPath: rulesDir, Language: "rego", Category: "Security", Input: ".*", Title: "A", Severity: "CRITICAL"},
+		"input":    {RulesPath: rulesDir, Language: "rego", Category: "Security", Input: "(", Title: "A", Severity: "LOW"},
+	}
+	for name, options := range invalid {
+		if _, _, err := NewRule(options); err == nil {
+			t.Errorf("%s: expected the options to be rejected", name)
+		}
+	}
+	if entries, _ := os.ReadDir(rulesDir); len(entries) != 0 {
+		t.Fatalf("expected no files to be written, got %d", len(entries))
+	}
+}
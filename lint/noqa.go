@@ -0,0 +1,103 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// documentationKey is the field Mendix uses for the documentation of a
+// document and of the elements inside it.
+const documentationKey = "Documentation"
+
+// findNoqaDirective returns the first line of documentation with a noqa
+// directive that applies to ruleNumber.
+func findNoqaDirective(documentation string, ruleNumber string) (string, bool) {
+	for _, line := range strings.Split(documentation, "\n") {
+		skipAll, rules, _ := parseNoqaDirective(line)
+		if skipAll {
+			return strings.TrimSpace(line), true
+		}
+		for _, rule := range rules {
+			if rule == ruleNumber {
+				return strings.TrimSpace(line), true
+			}
+		}
+	}
+	return "", false
+}
+
+func formatNoqaSkipReason(directive string) string {
+	return fmt.Sprintf("Skipped by %q in Documentation", directive)
+}
+
+// suppressNoqaViolations drops the violations located in a nested element of
+// data, such as an entity, attribute or microflow activity, whose
+// Documentation has a noqa directive for ruleNumber. Only violations with a
+// path can be suppressed; the document's own Documentation is handled by
+// shouldSkipRule. It returns the remaining violations and one skip reason per
+// directive that suppressed a violation.
+func suppressNoqaViolations(data map[string]interface{}, violations []Violation, ruleNumber string, ignoreNoqa bool) ([]Violation, []string) {
+	if ignoreNoqa || data == nil {
+		return violations, nil
+	}
+	remaining := make([]Violation, 0, len(violations))
+	reasons := make([]string, 0)
+	seen := map[string]bool{}
+	for _, violation := range violations {
+		reason, suppressed := nestedNoqaReason(data, violation.Path, ruleNumber)
+		if !suppressed {
+			remaining = append(remaining, violation)
+			continue
+		}
+		log.Debugf("Suppressed violation %q at %s: %s", violation.Message, violation.Path, reason)
+		if !seen[reason] {
+			seen[reason] = true
+			reasons = append(reasons, reason)
+		}
+	}
+	return remaining, reasons
+}
+
+// nestedNoqaReason follows a JSON pointer through data and returns the skip
+// reason of the innermost element on the way with a noqa directive for
+// ruleNumber. The document root is not considered.
+func nestedNoqaReason(data map[string]interface{}, pointer string, ruleNumber string) (string, bool) {
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return "", false
+	}
+	var current interface{} = data
+	path := ""
+	reason := ""
+	for _, rawToken := range strings.Split(pointer, "/") {
+		token := strings.ReplaceAll(strings.ReplaceAll(rawToken, "~1", "/"), "~0", "~")
+		switch value := current.(type) {
+		case map[string]interface{}:
+			current = value[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return reason, reason != ""
+			}
+			current = value[index]
+		default:
+			return reason, reason != ""
+		}
+		path += "/" + rawToken
+
+		element, ok := current.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		documentation, _ := element[documentationKey].(string)
+		if directive, found := findNoqaDirective(documentation, ruleNumber); found {
+			name := path
+			if elementName, ok := element["Name"].(string); ok && elementName != "" {
+				name = fmt.Sprintf("%s (%s)", elementName, path)
+			}
+			reason = fmt.Sprintf("Skipped by %q in the Documentation of %s", directive, name)
+		}
+	}
+	return reason, reason != ""
+}
@@ -0,0 +1,92 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSuppressNoqaViolations(t *testing.T) {
+	data := map[string]interface{}{
+		"Documentation": "",
+		"Entities": []interface{}{
+			map[string]interface{}{
+				"Name":          "Customer",
+				"Documentation": "Legacy entity\n#noqa:001_0001 kept for the old API",
+				"Attributes": []interface{}{
+					map[string]interface{}{"Name": "Code"},
+				},
+			},
+			map[string]interface{}{
+				"Name":          "Order",
+				"Documentation": "#noqa:001_0002",
+			},
+		},
+	}
+	violations := []Violation{
+		{Message: "attribute of Customer", Path: "/Entities/0/Attributes/0"},
+		{Message: "Customer", Path: "/Entities/0"},
+		{Message: "Order", Path: "/Entities/1"},
+		{Message: "document"},
+	}
+
+	remaining, reasons := suppressNoqaViolations(data, violations, "001_0001", false)
+	if len(remaining) != 2 || remaining[0].Message != "Order" || remaining[1].Message != "document" {
+		t.Fatalf("expected only the Customer violations to be suppressed, got %+v", remaining)
+	}
+	expectedReason := `Skipped by "#noqa:001_0001 kept for the old API" in the Documentation of Customer (/Entities/0)`
+	if len(reasons) != 1 || reasons[0] != expectedReason {
+		t.Fatalf("expected reason %q, got %v", expectedReason, reasons)
+	}
+
+	remaining, reasons = suppressNoqaViolations(data, violations, "001_0001", true)
+	if len(remaining) != len(violations) || len(reasons) != 0 {
+		t.Fatalf("expected ignoreNoqa to keep every violation, got %+v", remaining)
+	}
+}
+
+func TestEvalTestsuite_NestedNoqa(t *testing.T) {
+	modelDir := t.TempDir()
+	documents := map[string]string{
+		"Suppressed.yaml": "Entities:\n  - Name: Customer\n    Documentation: \"#noqa:001_0001\"\n",
+		"Partial.yaml":    "Entities:\n  - Name: Customer\n    Documentation: \"#noqa:001_0001\"\n  - Name: Order\n    Documentation: \"\"\n",
+	}
+	for name, content := range documents {
+		if err := writeTestFile(modelDir, name, content); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+	rule := Rule{
+		Path:       writeTestRule(t, "entities.js", "function rule(input) { const errors = input.Entities.map((e, i) => ({ message: e.Name + \" is invalid\", path: \"/Entities/\" + i })); return { allow: false, errors: errors }; }"),
+		RuleNumber: "001_0001",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJavascript,
+	}
+
+	testsuite, err := evalTestsuite(rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate testsuite: %v", err)
+	}
+	if testsuite.Skipped != 1 || testsuite.Failures != 1 {
+		t.Fatalf("expected one skipped and one failing document, got %+v", testsuite)
+	}
+	for _, testcase := range testsuite.Testcases {
+		switch testcase.Name {
+		case "Suppressed.yaml":
+			if testcase.Skipped == nil || !strings.Contains(testcase.Skipped.Message, "Customer (/Entities/0)") {
+				t.Fatalf("expected Suppressed.yaml to be skipped by the Customer directive, got %+v", testcase)
+			}
+		case "Partial.yaml":
+			if testcase.Failure == nil || testcase.Failure.Message != "Order is invalid" {
+				t.Fatalf("expected only the Order violation on Partial.yaml, got %+v", testcase)
+			}
+		}
+	}
+
+	testsuite, err = evalTestsuite(rule, modelDir, true, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate testsuite: %v", err)
+	}
+	if testsuite.Failures != 2 || testsuite.Skipped != 0 {
+		t.Fatalf("expected ignoreNoqa to report every violation, got %+v", testsuite)
+	}
+}
@@ -1,18 +1,23 @@
 package lint
 
-import "runtime"
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
 
 const defaultMaxLintConcurrency = 4
 
-func effectiveLintConcurrency(ruleCount int) int {
-	if ruleCount <= 0 {
+func effectiveLintConcurrency(workItems int) int {
+	if workItems <= 0 {
 		return 1
 	}
 
 	cfg := getConfig()
 	if cfg != nil && cfg.Lint.Concurrency != nil && *cfg.Lint.Concurrency > 0 {
-		if *cfg.Lint.Concurrency > ruleCount {
-			return ruleCount
+		if *cfg.Lint.Concurrency > workItems {
+			return workItems
 		}
 		return *cfg.Lint.Concurrency
 	}
@@ -24,8 +29,8 @@ func effectiveLintConcurrency(ruleCount int) int {
 	if auto > defaultMaxLintConcurrency {
 		auto = defaultMaxLintConcurrency
 	}
-	if auto > ruleCount {
-		auto = ruleCount
+	if auto > workItems {
+		auto = workItems
 	}
 	return auto
 }
@@ -34,3 +39,56 @@ func regoTraceEnabled() bool {
 	cfg := getConfig()
 	return cfg != nil && cfg.Lint.RegoTrace != nil && *cfg.Lint.RegoTrace
 }
+
+// ruleTimeout returns lint.ruleTimeout, or 0 when rule evaluations have no
+// deadline. Invalid values are rejected by parseRuleTimeout before linting.
+func ruleTimeout() time.Duration {
+	cfg := getConfig()
+	if cfg == nil {
+		return 0
+	}
+	timeout, _ := parseRuleTimeout(cfg.Lint.RuleTimeout)
+	return timeout
+}
+
+func parseRuleTimeout(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid lint.ruleTimeout %q: expected a duration such as 30s", value)
+	}
+	return timeout, nil
+}
+
+// failOnRuleError reports whether rule errors fail lint. It defaults to true.
+func failOnRuleError() bool {
+	cfg := getConfig()
+	return cfg == nil || cfg.Lint.FailOnRuleError == nil || *cfg.Lint.FailOnRuleError
+}
+
+func skipExpiryWarningDays() int {
+	cfg := getConfig()
+	if cfg == nil || cfg.Lint.SkipExpiryWarningDays == nil || *cfg.Lint.SkipExpiryWarningDays < 0 {
+		return 0
+	}
+	return *cfg.Lint.SkipExpiryWarningDays
+}
+
+func baselineFilePath() string {
+	cfg := getConfig()
+	if cfg == nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.Lint.Baseline)
+}
+
+func failOnThreshold() string {
+	cfg := getConfig()
+	if cfg == nil {
+		return ""
+	}
+	return normalizeSeverity(cfg.Lint.FailOn)
+}
@@ -1,6 +1,9 @@
 package lint
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func intPtr(v int) *int {
 	return &v
@@ -68,3 +71,26 @@ func TestRegoTraceEnabled(t *testing.T) {
 		t.Fatal("expected regoTraceEnabled to return true")
 	}
 }
+
+func TestParseRuleTimeout(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{value: "", expected: 0},
+		{value: "30s", expected: 30 * time.Second},
+		{value: " 250ms ", expected: 250 * time.Millisecond},
+		{value: "30", wantErr: true},
+		{value: "-1s", wantErr: true},
+	}
+	for _, tt := range tests {
+		value, err := parseRuleTimeout(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("parseRuleTimeout(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if value != tt.expected {
+			t.Fatalf("parseRuleTimeout(%q) = %v, expected %v", tt.value, value, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConfigRuleOverride replaces rule metadata for one rule number. Empty fields
+// keep the value from the rule file.
+type ConfigRuleOverride struct {
+	Severity    string `yaml:"severity,omitempty"`
+	Category    string `yaml:"category,omitempty"`
+	Remediation string `yaml:"remediation,omitempty"`
+}
+
+func (o ConfigRuleOverride) merge(overlay ConfigRuleOverride) ConfigRuleOverride {
+	if strings.TrimSpace(overlay.Severity) != "" {
+		o.Severity = strings.TrimSpace(overlay.Severity)
+	}
+	if strings.TrimSpace(overlay.Category) != "" {
+		o.Category = strings.TrimSpace(overlay.Category)
+	}
+	if strings.TrimSpace(overlay.Remediation) != "" {
+		o.Remediation = strings.TrimSpace(overlay.Remediation)
+	}
+	return o
+}
+
+// applyRuleOverrides applies rules.overrides to the parsed rules. The values
+// from the rule file are kept in Rule.Original.
+func applyRuleOverrides(rules []Rule) error {
+	cfg := getConfig()
+	if cfg == nil || len(cfg.Rules.Overrides) == 0 {
+		return nil
+	}
+
+	for ruleNumber, override := range cfg.Rules.Overrides {
+		if override.Severity != "" {
+			if err := validateSeverity(override.Severity); err != nil {
+				return fmt.Errorf("invalid rules.overrides severity for %s: %w", ruleNumber, err)
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	for i := range rules {
+		rule := &rules[i]
+		override, ok := cfg.Rules.Overrides[rule.RuleNumber]
+		if !ok || rule.RuleNumber == "" {
+			continue
+		}
+		used[rule.RuleNumber] = true
+
+		original := RuleOriginal{
+			Severity:    rule.Severity,
+			Category:    rule.Category,
+			Remediation: rule.Remediation,
+		}
+		if override.Severity != "" {
+			rule.Severity = normalizeSeverity(override.Severity)
+		}
+		if override.Category != "" {
+			rule.Category = override.Category
+		}
+		if override.Remediation != "" {
+			rule.Remediation = override.Remediation
+		}
+		if original != (RuleOriginal{Severity: rule.Severity, Category: rule.Category, Remediation: rule.Remediation}) {
+			rule.Original = &original
+			log.Debugf("Applied rules.overrides to %s", rule.Path)
+		}
+	}
+
+	unused := make([]string, 0)
+	for ruleNumber := range cfg.Rules.Overrides {
+		if !used[ruleNumber] {
+			unused = append(unused, ruleNumber)
+		}
+	}
+	slices.Sort(unused)
+	for _, ruleNumber := range unused {
+		log.Warnf("rules.overrides entry %s does not match any rule", ruleNumber)
+	}
+	return nil
+}
+
+// loadRules reads rule metadata and applies rules.overrides and rule
+// selection, in that order, so selectors see the overridden values.
+func loadRules(rulesPath string) ([]Rule, error) {
+	rules, err := ReadRulesMetadata(rulesPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRuleOverrides(rules); err != nil {
+		return nil, err
+	}
+	return selectRules(rules)
+}
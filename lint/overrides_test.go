@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyRuleOverrides(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{Rules: ConfigRulesSpec{Overrides: map[string]ConfigRuleOverride{
+		"001_0001": {Severity: "high", Remediation: "Ask the security team"},
+		"001_0002": {Category: "Security"},
+		"009_9999": {Severity: "LOW"},
+	}}})
+
+	rules := []Rule{
+		{Path: "a.rego", RuleNumber: "001_0001", Category: "Security", Severity: "MEDIUM", Remediation: "Fix it"},
+		{Path: "b.js", RuleNumber: "001_0002", Category: "Security", Severity: "LOW"},
+		{Path: "c.rego", RuleNumber: "002_0001", Category: "Maintainability", Severity: "LOW"},
+	}
+	if err := applyRuleOverrides(rules); err != nil {
+		t.Fatalf("applyRuleOverrides returned error: %v", err)
+	}
+
+	if rules[0].Severity != "HIGH" || rules[0].Remediation != "Ask the security team" || rules[0].Category != "Security" {
+		t.Fatalf("expected overridden severity and remediation, got %+v", rules[0])
+	}
+	if rules[0].Original == nil || rules[0].Original.Severity != "MEDIUM" || rules[0].Original.Remediation != "Fix it" {
+		t.Fatalf("expected original values to be kept, got %+v", rules[0].Original)
+	}
+	if rules[1].Original != nil {
+		t.Fatalf("expected no original when the override changes nothing, got %+v", rules[1].Original)
+	}
+	if rules[2].Original != nil || rules[2].Severity != "LOW" {
+		t.Fatalf("expected rule without override to be untouched, got %+v", rules[2])
+	}
+}
+
+func TestApplyRuleOverrides_InvalidSeverity(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{Rules: ConfigRulesSpec{Overrides: map[string]ConfigRuleOverride{
+		"001_0001": {Severity: "BLOCKER"},
+	}}})
+
+	if err := applyRuleOverrides([]Rule{{RuleNumber: "001_0001"}}); err == nil {
+		t.Fatal("expected error for unknown override severity")
+	}
+}
+
+func TestLoadMergedConfig_RuleOverrides(t *testing.T) {
+	projectDir := t.TempDir()
+	setDefaultConfigForTest(t, `rules:
+  overrides:
+    001_0001:
+      severity: MEDIUM
+      remediation: default remediation
+`)
+	projectConfig := `rules:
+  overrides:
+    001_0001:
+      severity: HIGH
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "mxlint.yaml"), []byte(projectConfig), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+
+	cfg, err := LoadMergedConfig(projectDir)
+	if err != nil {
+		t.Fatalf("LoadMergedConfig returned error: %v", err)
+	}
+	override := cfg.Rules.Overrides["001_0001"]
+	if override.Severity != "HIGH" || override.Remediation != "default remediation" {
+		t.Fatalf("expected field-wise merged override, got %+v", override)
+	}
+}
+
+func TestEvalAll_OverriddenSeverityDrivesFailOn(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	writeAlwaysFailRuleWithSeverity(t, rulesDir, "099_0001", "MEDIUM")
+	if err := writeTestFile(modelDir, "Doc.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	SetConfig(&Config{Lint: ConfigLintSpec{FailOn: "HIGH"}})
+	if err := EvalAll(rulesDir, modelDir, "", "", "", false, false, nil); err != nil {
+		t.Fatalf("expected MEDIUM rule to only warn, got: %v", err)
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "report.json")
+	SetConfig(&Config{
+		Lint: ConfigLintSpec{FailOn: "HIGH"},
+		Rules: ConfigRulesSpec{Overrides: map[string]ConfigRuleOverride{
+			"099_0001": {Severity: "HIGH"},
+		}},
+	})
+	if err := EvalAll(rulesDir, modelDir, "", jsonPath, "", false, false, nil); err == nil {
+		t.Fatal("expected overridden HIGH rule to fail")
+	}
+
+	content, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("expected JSON report: %v", err)
+	}
+	var report TestSuites
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("failed to parse JSON report: %v", err)
+	}
+	rule := report.Rules[0]
+	if rule.Severity != "HIGH" || rule.Original == nil || rule.Original.Severity != "MEDIUM" {
+		t.Fatalf("expected overridden severity with original in JSON report, got %+v", rule)
+	}
+}
@@ -0,0 +1,104 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/cover"
+	"github.com/open-policy-agent/opa/tester"
+)
+
+// regoTestFilePath returns the OPA-native test module next to a Rego rule.
+func regoTestFilePath(rule Rule) string {
+	return strings.TrimSuffix(rule.Path, ".rego") + "_test.rego"
+}
+
+// parseRegoModule parses a rule or test module the way prepareRegoRule
+// compiles it, with METADATA annotations, so coverage locations match.
+func parseRegoModule(path string) (*ast.Module, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ast.ParseModuleWithOpts(path, quoteRegoMetadataRulenumber(string(content)), ast.ParserOptions{ProcessAnnotation: true})
+}
+
+// runRegoNativeTests runs the test_ rules of testPath against the rule with
+// OPA's tester and returns a testcase per test rule. Evaluated expressions are
+// recorded in coverage.
+func runRegoNativeTests(rule Rule, testPath string, coverage *cover.Cover) []Testcase {
+	modules := map[string]*ast.Module{}
+	for _, path := range []string{rule.Path, testPath} {
+		module, err := parseRegoModule(path)
+		if err != nil {
+			return []Testcase{{
+				Name:  testPath,
+				Error: newRuleError(rule.Path, testPath, newRuleEvalError(ruleErrorLoad, err)),
+			}}
+		}
+		modules[path] = module
+	}
+
+	runner := tester.NewRunner().
+		SetModules(modules).
+		SetStore(regoDataStore(testCaseSettings(rule, nil))).
+		SetCoverageQueryTracer(coverage).
+		SetParallel(1)
+	ch, err := runner.RunTests(context.Background(), nil)
+	if err != nil {
+		return []Testcase{{
+			Name:  testPath,
+			Error: newRuleError(rule.Path, testPath, newRuleEvalError(ruleErrorLoad, err)),
+		}}
+	}
+
+	results := make([]*tester.Result, 0)
+	for result := range ch {
+		results = append(results, result)
+	}
+	// Results arrive in any order; report them in the order of the file.
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Location.Row < results[j].Location.Row
+	})
+
+	testcases := make([]Testcase, 0, len(results))
+	for _, result := range results {
+		testcase := Testcase{
+			Name: result.Name,
+			Time: result.Duration.Seconds(),
+		}
+		switch {
+		case result.Error != nil:
+			testcase.Error = newRuleError(rule.Path, result.Name, result.Error)
+		case result.Skip:
+			testcase.Skipped = &Skipped{Message: "skipped by the todo_ prefix"}
+		case result.Fail:
+			message := fmt.Sprintf("%s is false or undefined", result.Name)
+			if result.FailedAt != nil && result.FailedAt.Location != nil {
+				message = fmt.Sprintf("%s failed at %s:%d: %s", result.Name, testPath, result.FailedAt.Location.Row, result.FailedAt.String())
+			}
+			testcase.Failure = &Failure{Message: message, Type: "AssertionError"}
+		}
+		testcases = append(testcases, testcase)
+	}
+	return testcases
+}
+
+// regoCoverage returns the percentage of the rule module evaluated by its
+// test cases.
+func regoCoverage(rule Rule, coverage *cover.Cover) (float64, error) {
+	module, err := parseRegoModule(rule.Path)
+	if err != nil {
+		return 0, err
+	}
+	report := coverage.Report(map[string]*ast.Module{rule.Path: module})
+	file, ok := report.Files[rule.Path]
+	if !ok {
+		return 0, nil
+	}
+	return file.Coverage, nil
+}
@@ -0,0 +1,118 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+const testNativeRego = `# METADATA
+# scope: package
+# title: Entities need documentation
+# description: Entities need documentation
+# custom:
+#  rulenumber: 002_0501
+#  input: .*DomainModel.yaml
+package app.mendix.test.native
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains sprintf("Entity %v has no documentation", [entity.Name]) if {
+    some entity in input.Entities
+    entity.Documentation == ""
+}
+
+errors contains "Domain model has no entities" if {
+    count(input.Entities) == 0
+}
+`
+
+const testNativeRegoTests = `package app.mendix.test.native_test
+import rego.v1
+
+import data.app.mendix.test.native
+
+test_allow if {
+    native.allow with input as {"Entities": [{"Name": "Customer", "Documentation": "Customers"}]}
+}
+
+test_wrong_count if {
+    count(native.errors) == 2 with input as {"Entities": [{"Name": "Customer", "Documentation": ""}]}
+}
+
+todo_test_empty if {
+    native.errors == {"Domain model has no entities"} with input as {"Entities": []}
+}
+`
+
+func TestTestAll_RegoNativeTests(t *testing.T) {
+	rulesDir := t.TempDir()
+	files := map[string]string{
+		"002_0501_native.rego":      testNativeRego,
+		"002_0501_native_test.rego": testNativeRegoTests,
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+	rules, err := ReadRulesMetadata(rulesDir)
+	if err != nil {
+		t.Fatalf("Failed to read rules: %v", err)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("expected the _test.rego module not to be read as a rule, got %+v", rules)
+	}
+
+	testsuite, err := runTestCases(rules[0], 90)
+	if err == nil || !strings.Contains(err.Error(), "test_wrong_count") {
+		t.Fatalf("expected test_wrong_count to fail, got %v", err)
+	}
+	if testsuite.Tests != 4 || testsuite.Failures != 2 || testsuite.Skipped != 1 || testsuite.Errors != 0 {
+		t.Fatalf("expected 3 test rules and a coverage check, got %+v", testsuite)
+	}
+	names := make([]string, 0, len(testsuite.Testcases))
+	for _, testcase := range testsuite.Testcases {
+		names = append(names, testcase.Name)
+	}
+	if strings.Join(names, ",") != "test_allow,test_wrong_count,todo_test_empty,coverage" {
+		t.Fatalf("expected the test rules in file order, got %v", names)
+	}
+	if testsuite.Coverage == nil || *testsuite.Coverage <= 0 || *testsuite.Coverage >= 90 {
+		t.Fatalf("expected partial coverage, got %v", testsuite.Coverage)
+	}
+	if failure := testsuite.Testcases[3].Failure; failure == nil || failure.Type != "CoverageError" {
+		t.Fatalf("expected the coverage to fail the threshold, got %+v", testsuite.Testcases[3])
+	}
+
+	// The empty domain model case is covered once a YAML test case runs it.
+	cases := "TestCases:\n- name: empty\n  input:\n    Entities: []\n  errors:\n    - Domain model has no entities\n"
+	if err := writeTestFile(rulesDir, "002_0501_native_test.yaml", cases); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	covered, _ := runTestCases(rules[0], 0)
+	if covered.Tests != 4 || covered.Failures != 1 {
+		t.Fatalf("expected the YAML case to run alongside the test rules, got %+v", covered)
+	}
+	if *covered.Coverage <= *testsuite.Coverage {
+		t.Fatalf("expected the YAML case to add coverage, got %v after %v", *covered.Coverage, *testsuite.Coverage)
+	}
+}
+
+func TestTestAll_RegoNativeTestsCompileError(t *testing.T) {
+	rulesDir := t.TempDir()
+	files := map[string]string{
+		"002_0502_native.rego":      strings.Replace(testNativeRego, "002_0501", "002_0502", 1),
+		"002_0502_native_test.rego": "package app.mendix.test.native_test\nimport rego.v1\n\ntest_undefined if {\n    data.app.mendix.test.native.allow == undefined_var\n}\n",
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+	err := TestAll(rulesDir)
+	if err == nil || !strings.Contains(err.Error(), "1 rule test(s) failed") {
+		t.Fatalf("expected the test module to fail to compile, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RuleError types. A rule error means the rule could not judge the document,
+// as opposed to a failure, which means the document violates the rule.
+const (
+	// ruleErrorLoad is a rule that cannot be read, compiled or does not
+	// define its entrypoint.
+	ruleErrorLoad = "LoadError"
+	// ruleErrorRuntime is a rule that threw or failed during evaluation.
+	ruleErrorRuntime = "RuntimeError"
+	// ruleErrorContract is a rule whose result is not {allow, errors}.
+	ruleErrorContract = "ContractError"
+)
+
+// ruleEvalError classifies an error caused by the rule rather than by the
+// document it evaluates.
+type ruleEvalError struct {
+	kind string
+	err  error
+}
+
+func (e *ruleEvalError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ruleEvalError) Unwrap() error {
+	return e.err
+}
+
+func newRuleEvalError(kind string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &ruleEvalError{kind: kind, err: err}
+}
+
+// ruleErrorType returns the RuleError type of err. Errors that were not
+// classified by the evaluator are runtime errors.
+func ruleErrorType(err error) string {
+	var ruleErr *ruleEvalError
+	if errors.As(err, &ruleErr) {
+		return ruleErr.kind
+	}
+	return ruleErrorRuntime
+}
+
+func newRuleError(rulePath string, inputFilePath string, err error) *RuleError {
+	return &RuleError{
+		Message: fmt.Sprintf("Rule %s could not be evaluated on %s: %v", rulePath, inputFilePath, err),
+		Type:    ruleErrorType(err),
+	}
+}
+
+// newRuleErrorTestcase records an evaluation that produced no result because
+// the rule is broken. Callers log the error, so that a rule that fails to load
+// is logged once rather than once per document.
+func newRuleErrorTestcase(rulePath string, inputFilePath string, err error, duration time.Duration) *Testcase {
+	return &Testcase{
+		Name:  inputFilePath,
+		Time:  float64(duration.Nanoseconds()) / 1e9, // convert to seconds
+		Error: newRuleError(rulePath, inputFilePath, err),
+	}
+}
+
+// parseRuleResult extracts allow and errors from a rule result object. A
+// result without a boolean allow, or with allow false and no errors list, is
+// a contract error.
+func parseRuleResult(value interface{}) (bool, []interface{}, error) {
+	result, ok := value.(map[string]interface{})
+	if !ok {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("rule result must be an object with allow and errors, got %T", value))
+	}
+	allow, ok := result["allow"].(bool)
+	if !ok {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("rule result must contain a boolean allow, got %T", result["allow"]))
+	}
+	if result["errors"] == nil {
+		if !allow {
+			return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("rule result must contain errors when allow is false"))
+		}
+		return allow, nil, nil
+	}
+	errors, ok := result["errors"].([]interface{})
+	if !ok {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("rule result errors must be a list, got %T", result["errors"]))
+	}
+	return allow, errors, nil
+}
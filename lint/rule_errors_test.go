@@ -0,0 +1,187 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvalTestsuites_RuleErrorsDoNotStopRun(t *testing.T) {
+	modelDir := t.TempDir()
+	for _, name := range []string{"A.yaml", "B.yaml"} {
+		if err := writeTestFile(modelDir, name, "Name: Test\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	rules := []Rule{
+		{Path: writeTestRule(t, "syntax.js", "function rule(input) {"), Language: LanguageJavascript},
+		{Path: writeTestRule(t, "norule.js", "const metadata = {};"), Language: LanguageJavascript},
+		{Path: writeTestRule(t, "throws.js", "function rule(input) { throw new Error(\"boom\"); }"), Language: LanguageJavascript},
+		{Path: writeTestRule(t, "noallow.js", "function rule(input) { return { errors: [] }; }"), Language: LanguageJavascript},
+		{Path: writeTestRule(t, "noerrors.js", "function rule(input) { return { allow: false }; }"), Language: LanguageJavascript},
+		{Path: writeTestRule(t, "throws.ts", "function rule(input: any): any { throw new Error(\"boom\"); }"), Language: LanguageTypescript},
+		{Path: writeTestRule(t, "syntax.rego", "package test.syntax\n\nallow if {\n"), PackageName: "test.syntax", Language: LanguageRego},
+		{Path: writeTestRule(t, "conflict.rego", "package test.conflict\n\nimport rego.v1\n\nname := input.Name\nname := \"Other\"\nallow := true\nerrors := []\n"), PackageName: "test.conflict", Language: LanguageRego},
+		{Path: writeTestRule(t, "stringallow.rego", "package test.stringallow\n\nimport rego.v1\n\nallow := \"yes\"\nerrors := []\n"), PackageName: "test.stringallow", Language: LanguageRego},
+		{Path: writeTestRule(t, "passing.js", "function rule(input) { return { allow: true, errors: [] }; }"), Language: LanguageJavascript},
+	}
+	expected := []string{ruleErrorLoad, ruleErrorLoad, ruleErrorRuntime, ruleErrorContract, ruleErrorContract, ruleErrorRuntime, ruleErrorLoad, ruleErrorRuntime, ruleErrorContract, ""}
+	for i := range rules {
+		rules[i].Pattern = ".*\\.yaml"
+	}
+
+	run, err := newLintRun(modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to index modelsource: %v", err)
+	}
+	testsuites, err := run.evalTestsuites(rules, nil)
+	if err != nil {
+		t.Fatalf("evalTestsuites returned error: %v", err)
+	}
+
+	for i, testsuite := range testsuites {
+		if testsuite.Tests != 2 || testsuite.Failures != 0 {
+			t.Fatalf("%s: expected two testcases without failures, got %+v", filepath.Base(testsuite.Name), testsuite)
+		}
+		if expected[i] == "" {
+			if testsuite.Errors != 0 {
+				t.Fatalf("%s: expected no rule errors, got %+v", filepath.Base(testsuite.Name), testsuite.Testcases)
+			}
+			continue
+		}
+		if testsuite.Errors != 2 {
+			t.Fatalf("%s: expected two rule errors, got %+v", filepath.Base(testsuite.Name), testsuite.Testcases)
+		}
+		for _, testcase := range testsuite.Testcases {
+			if testcase.Error.Type != expected[i] {
+				t.Fatalf("%s: expected %s, got %s: %s", filepath.Base(testsuite.Name), expected[i], testcase.Error.Type, testcase.Error.Message)
+			}
+			if !strings.Contains(testcase.Error.Message, testsuite.Name) {
+				t.Fatalf("%s: expected message to name the rule, got %q", filepath.Base(testsuite.Name), testcase.Error.Message)
+			}
+		}
+	}
+}
+
+func TestEvalProjectTestsuite_RuleError(t *testing.T) {
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "Metadata.yaml", "ProductVersion: 10.0.0\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	if err := writeTestFile(modelDir, "A.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	rule := Rule{
+		Path:     writeTestRule(t, "project.js", "function rule(input) { return { allow: \"no\", errors: [] }; }"),
+		Pattern:  ".*\\.yaml",
+		Language: LanguageJavascript,
+		Scope:    ScopeProject,
+	}
+
+	testsuite, err := evalTestsuite(rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("evalTestsuite returned error: %v", err)
+	}
+	if testsuite.Errors != 1 || testsuite.Failures != 0 {
+		t.Fatalf("expected one rule error and no failures, got %+v", testsuite)
+	}
+	for _, testcase := range testsuite.Testcases {
+		if testcase.Name == projectMetadataDocument {
+			if testcase.Error == nil || testcase.Error.Type != ruleErrorContract {
+				t.Fatalf("expected ContractError on %s, got %+v", projectMetadataDocument, testcase)
+			}
+		} else if testcase.Error != nil {
+			t.Fatalf("expected only %s to carry the rule error, got %+v", projectMetadataDocument, testcase)
+		}
+	}
+}
+
+func TestEvalAll_FailOnRuleError(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	ruleContent := `
+const metadata = {
+    title: "Broken Rule",
+    description: "This rule always throws",
+    custom: {
+        category: "Test",
+        severity: "HIGH",
+        rulenumber: "099_0001",
+        input: ".*\\.yaml"
+    }
+};
+
+function rule(input) {
+    throw new Error("boom");
+}
+`
+	if err := writeTestFile(rulesDir, "099_0001_broken.js", ruleContent); err != nil {
+		t.Fatalf("Failed to write rule file: %v", err)
+	}
+	if err := writeTestFile(modelDir, "Doc.yaml", `Name: "Test"`); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	xunitReport := filepath.Join(t.TempDir(), "report.xml")
+
+	SetConfig(&Config{})
+	if err := EvalAll(rulesDir, modelDir, xunitReport, "", "", false, false, nil); err == nil || !strings.Contains(err.Error(), "1 rule errors") {
+		t.Fatalf("expected rule error to fail lint by default, got: %v", err)
+	}
+	report, err := os.ReadFile(xunitReport)
+	if err != nil {
+		t.Fatalf("Failed to read xunit report: %v", err)
+	}
+	if !strings.Contains(string(report), `errors="1"`) || !strings.Contains(string(report), `<error message=`) {
+		t.Fatalf("expected rule error in xunit report, got:\n%s", report)
+	}
+
+	failOnRuleError := false
+	SetConfig(&Config{Lint: ConfigLintSpec{FailOnRuleError: &failOnRuleError}})
+	result, err := EvalAllWithResults(rulesDir, modelDir, "", "", "", false, false, nil)
+	if err != nil {
+		t.Fatalf("expected rule error not to fail lint with failOnRuleError false, got: %v", err)
+	}
+	if suite := result.(TestSuites).Testsuites[0]; suite.Errors != 1 || suite.Failures != 0 {
+		t.Fatalf("expected one rule error and no failures, got %+v", suite)
+	}
+}
+
+func TestParseRuleResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		allow    bool
+		contract bool
+	}{
+		{name: "pass", value: map[string]interface{}{"allow": true, "errors": []interface{}{}}, allow: true},
+		{name: "pass without errors", value: map[string]interface{}{"allow": true}, allow: true},
+		{name: "fail", value: map[string]interface{}{"allow": false, "errors": []interface{}{"bad"}}},
+		{name: "not an object", value: "allow", contract: true},
+		{name: "missing allow", value: map[string]interface{}{"errors": []interface{}{}}, contract: true},
+		{name: "fail without errors", value: map[string]interface{}{"allow": false}, contract: true},
+		{name: "errors not a list", value: map[string]interface{}{"allow": false, "errors": "bad"}, contract: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allow, _, err := parseRuleResult(tt.value)
+			if tt.contract {
+				if err == nil || ruleErrorType(err) != ruleErrorContract {
+					t.Fatalf("expected ContractError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if allow != tt.allow {
+				t.Fatalf("expected allow %v, got %v", tt.allow, allow)
+			}
+		})
+	}
+}
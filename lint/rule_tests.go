@@ -0,0 +1,238 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ruleTestFileError is the RuleError type of a test case that cannot run
+// because the _test.yaml file or the case itself is invalid.
+const ruleTestFileError = "TestFileError"
+
+// ruleTestCase is one entry of the TestCases list of a _test.yaml file. At
+// least one of allow, errors and errorCount is asserted.
+type ruleTestCase struct {
+	name       string
+	input      map[string]interface{}
+	allow      *bool
+	errors     []errorExpectation
+	errorCount *int
+	// raw is the entry as read, for the settings overlay.
+	raw interface{}
+}
+
+// errorExpectation matches the message of a reported error: a plain string
+// matches it exactly, {contains: ...} matches a substring and {regex: ...} a
+// regular expression.
+type errorExpectation struct {
+	exact    string
+	contains string
+	regex    *regexp.Regexp
+}
+
+func (e errorExpectation) matches(message string) bool {
+	switch {
+	case e.regex != nil:
+		return e.regex.MatchString(message)
+	case e.contains != "":
+		return strings.Contains(message, e.contains)
+	default:
+		return message == e.exact
+	}
+}
+
+func (e errorExpectation) String() string {
+	switch {
+	case e.regex != nil:
+		return fmt.Sprintf("an error matching %q", e.regex.String())
+	case e.contains != "":
+		return fmt.Sprintf("an error containing %q", e.contains)
+	default:
+		return fmt.Sprintf("the error %q", e.exact)
+	}
+}
+
+// ruleTestFilePath returns the _test.yaml file next to a rule.
+func ruleTestFilePath(rule Rule) string {
+	if rule.Language == LanguageJSONSchema {
+		return strings.TrimSuffix(rule.Path, schemaRuleSuffix) + "_test.yaml"
+	}
+	return strings.TrimSuffix(rule.Path, filepath.Ext(rule.Path)) + "_test.yaml"
+}
+
+// ruleTestCaseName returns the name of a raw test case for reporting, also
+// when the case is invalid.
+func ruleTestCaseName(raw interface{}, index int) string {
+	if tcMap, ok := toStringKeyMap(raw); ok {
+		if name, ok := tcMap["name"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("test case %d", index+1)
+}
+
+func toStringKeyMap(value interface{}) (map[string]interface{}, bool) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		return typed, true
+	case map[interface{}]interface{}:
+		return convertToStringKeyMap(typed), true
+	}
+	return nil, false
+}
+
+// parseRuleTestCase reads a raw test case. A relative inputFile is resolved
+// against the directory of the test file.
+func parseRuleTestCase(raw interface{}, index int, testFilePath string) (*ruleTestCase, error) {
+	tcMap, ok := toStringKeyMap(raw)
+	if !ok {
+		return nil, fmt.Errorf("test case must be a mapping, got %T", raw)
+	}
+	testCase := &ruleTestCase{name: ruleTestCaseName(raw, index), raw: raw}
+
+	inputFile, hasInputFile := tcMap["inputFile"]
+	if _, hasInput := tcMap["input"]; hasInput && hasInputFile {
+		return nil, fmt.Errorf("input and inputFile are mutually exclusive")
+	}
+	if hasInputFile {
+		input, err := readTestInputFile(inputFile, testFilePath)
+		if err != nil {
+			return nil, err
+		}
+		testCase.input = input
+	} else {
+		input, ok := toStringKeyMap(tcMap["input"])
+		if !ok {
+			return nil, fmt.Errorf("input must be a mapping, got %T", tcMap["input"])
+		}
+		testCase.input = input
+	}
+
+	if value, ok := tcMap["allow"]; ok {
+		allow, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("allow must be a boolean, got %T", value)
+		}
+		testCase.allow = &allow
+	}
+	if value, ok := tcMap["errorCount"]; ok {
+		count, ok := value.(int)
+		if !ok || count < 0 {
+			return nil, fmt.Errorf("errorCount must be a non-negative integer, got %v", value)
+		}
+		testCase.errorCount = &count
+	}
+	if value, ok := tcMap["errors"]; ok {
+		entries, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("errors must be a list, got %T", value)
+		}
+		for _, entry := range entries {
+			expectation, err := parseErrorExpectation(entry)
+			if err != nil {
+				return nil, err
+			}
+			testCase.errors = append(testCase.errors, expectation)
+		}
+	}
+	if testCase.allow == nil && testCase.errorCount == nil && testCase.errors == nil {
+		return nil, fmt.Errorf("test case asserts nothing; set allow, errors or errorCount")
+	}
+	return testCase, nil
+}
+
+func parseErrorExpectation(entry interface{}) (errorExpectation, error) {
+	if message, ok := entry.(string); ok {
+		return errorExpectation{exact: message}, nil
+	}
+	object, ok := toStringKeyMap(entry)
+	if !ok || len(object) != 1 {
+		return errorExpectation{}, fmt.Errorf("errors entries must be a message, {contains: ...} or {regex: ...}, got %v", entry)
+	}
+	if contains, ok := object["contains"].(string); ok && contains != "" {
+		return errorExpectation{contains: contains}, nil
+	}
+	if pattern, ok := object["regex"].(string); ok {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return errorExpectation{}, fmt.Errorf("invalid errors regex %q: %w", pattern, err)
+		}
+		return errorExpectation{regex: regex}, nil
+	}
+	return errorExpectation{}, fmt.Errorf("errors entries must be a message, {contains: ...} or {regex: ...}, got %v", entry)
+}
+
+// readTestInputFile reads the input of a test case from an exported YAML or
+// JSON document.
+func readTestInputFile(value interface{}, testFilePath string) (map[string]interface{}, error) {
+	path, ok := value.(string)
+	if !ok || path == "" {
+		return nil, fmt.Errorf("inputFile must be a path, got %v", value)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(testFilePath), path)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		input, err := readYAMLDocumentFromPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inputFile %s: %w", path, err)
+		}
+		return input, nil
+	case ".json":
+		data, err := readJSONDocumentFromPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inputFile %s: %w", path, err)
+		}
+		input, ok := data.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("inputFile %s must contain an object", path)
+		}
+		return input, nil
+	}
+	return nil, fmt.Errorf("inputFile %s must be a .yaml or .json document", path)
+}
+
+// check compares the result of a rule with the assertions of the test case
+// and returns the mismatches.
+func (tc *ruleTestCase) check(allow bool, violations []Violation) []string {
+	mismatches := make([]string, 0)
+	if tc.allow != nil && *tc.allow != allow {
+		mismatches = append(mismatches, fmt.Sprintf("expected allow %v, got %v", *tc.allow, allow))
+	}
+	if tc.errorCount != nil && *tc.errorCount != len(violations) {
+		mismatches = append(mismatches, fmt.Sprintf("expected %d error(s), got %d", *tc.errorCount, len(violations)))
+	}
+	for _, expectation := range tc.errors {
+		matched := false
+		for _, v := range violations {
+			if expectation.matches(v.Message) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			mismatches = append(mismatches, "expected "+expectation.String())
+		}
+	}
+	return mismatches
+}
+
+// newRuleTestFailure describes the mismatches of a test case together with
+// the errors the rule actually reported.
+func newRuleTestFailure(mismatches []string, violations []Violation) *Failure {
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, fmt.Sprintf("%q", v.Message))
+	}
+	got := "no errors"
+	if len(messages) > 0 {
+		got = "errors " + strings.Join(messages, ", ")
+	}
+	return &Failure{
+		Message: fmt.Sprintf("%s; got %s", strings.Join(mismatches, "; "), got),
+		Type:    "AssertionError",
+	}
+}
@@ -0,0 +1,176 @@
+package lint
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testAssertionRego = `# METADATA
+# scope: package
+# title: Entities need a name and documentation
+# description: Entities need a name and documentation
+# custom:
+#  rulenumber: 002_0401
+#  input: .*DomainModel.yaml
+package app.mendix.test.assertions
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains sprintf("Entity %v has no documentation", [entity.Name]) if {
+    some entity in input.Entities
+    entity.Documentation == ""
+}
+`
+
+const testAssertionJavascript = `const metadata = {
+    title: "Entities need documentation",
+    description: "Entities need documentation",
+    custom: { rulenumber: "002_0402", input: ".*DomainModel.yaml" }
+};
+
+function rule(input) {
+    const errors = input.Entities
+        .filter(entity => entity.Documentation === "")
+        .map(entity => ({ message: "Entity " + entity.Name + " has no documentation", path: "/Entities" }));
+    return { allow: errors.length === 0, errors };
+}
+`
+
+const testAssertionCases = `TestCases:
+- name: exact
+  input:
+    Entities:
+      - Name: Customer
+        Documentation: ""
+  allow: false
+  errors:
+    - Entity Customer has no documentation
+- name: contains_and_count
+  input:
+    Entities:
+      - Name: Customer
+        Documentation: ""
+      - Name: Order
+        Documentation: ""
+  errorCount: 2
+  errors:
+    - contains: Order has no
+- name: regex_from_file
+  inputFile: testdata/DomainModels$DomainModel.yaml
+  errors:
+    - regex: "^Entity (Customer|Order) has no documentation$"
+  errorCount: 1
+`
+
+func writeAssertionRules(t *testing.T, cases string) string {
+	t.Helper()
+	rulesDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(rulesDir, "testdata"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	files := map[string]string{
+		"002_0401_documentation.rego":            testAssertionRego,
+		"002_0401_documentation_test.yaml":       cases,
+		"002_0402_documentation.js":              testAssertionJavascript,
+		"002_0402_documentation_test.yaml":       cases,
+		"testdata/DomainModels$DomainModel.yaml": "Entities:\n  - Name: Customer\n    Documentation: \"\"\n  - Name: Order\n    Documentation: Orders of a customer\n",
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+	return rulesDir
+}
+
+func TestTestAll_ErrorAssertions(t *testing.T) {
+	rulesDir := writeAssertionRules(t, testAssertionCases)
+	if err := TestAll(rulesDir); err != nil {
+		t.Fatalf("Expected rule tests to pass: %v", err)
+	}
+}
+
+func TestTestAll_RunsEveryCase(t *testing.T) {
+	cases := `TestCases:
+- name: wrong_message
+  input:
+    Entities:
+      - Name: Customer
+        Documentation: ""
+  errors:
+    - Entity Order has no documentation
+- name: wrong_count
+  input:
+    Entities: []
+  allow: true
+  errorCount: 1
+- name: passes
+  input:
+    Entities: []
+  allow: true
+- name: missing_file
+  inputFile: testdata/Missing.yaml
+  allow: true
+- name: no_assertion
+  input:
+    Entities: []
+`
+	rulesDir := writeAssertionRules(t, cases)
+	report := filepath.Join(t.TempDir(), "rule-tests.xml")
+	err := TestAllWithReport(rulesDir, report, 0)
+	if err == nil || !strings.Contains(err.Error(), "2 rule test(s) failed") {
+		t.Fatalf("expected both rules to fail, got %v", err)
+	}
+
+	content, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	var testsuites TestSuites
+	if err := xml.Unmarshal(content, &testsuites); err != nil {
+		t.Fatalf("Failed to parse report: %v", err)
+	}
+	if len(testsuites.Testsuites) != 2 {
+		t.Fatalf("expected a testsuite per rule, got %d", len(testsuites.Testsuites))
+	}
+	for _, testsuite := range testsuites.Testsuites {
+		if testsuite.Tests != 5 || testsuite.Failures != 2 || testsuite.Errors != 2 {
+			t.Fatalf("%s: expected 5 cases with 2 failures and 2 errors, got %+v", filepath.Base(testsuite.Name), testsuite)
+		}
+		failure := testsuite.Testcases[0].Failure
+		if failure == nil || !strings.Contains(failure.Message, `expected the error "Entity Order has no documentation"; got errors "Entity Customer has no documentation"`) {
+			t.Fatalf("%s: unexpected failure %+v", filepath.Base(testsuite.Name), failure)
+		}
+		if failure := testsuite.Testcases[1].Failure; failure == nil || !strings.Contains(failure.Message, "expected 1 error(s), got 0") {
+			t.Fatalf("%s: unexpected failure %+v", filepath.Base(testsuite.Name), failure)
+		}
+		if testsuite.Testcases[2].Failure != nil || testsuite.Testcases[2].Error != nil {
+			t.Fatalf("%s: expected the third case to pass", filepath.Base(testsuite.Name))
+		}
+		for _, testcase := range testsuite.Testcases[3:] {
+			if testcase.Error == nil || testcase.Error.Type != ruleTestFileError {
+				t.Fatalf("%s: expected %s to be an invalid test case, got %+v", filepath.Base(testsuite.Name), testcase.Name, testcase)
+			}
+		}
+	}
+}
+
+func TestParseRuleTestCase_Invalid(t *testing.T) {
+	invalid := map[string]map[string]interface{}{
+		"both inputs": {"input": map[string]interface{}{}, "inputFile": "a.yaml", "allow": true},
+		"bad regex":   {"input": map[string]interface{}{}, "errors": []interface{}{map[string]interface{}{"regex": "("}}},
+		"bad entry":   {"input": map[string]interface{}{}, "errors": []interface{}{map[string]interface{}{"startsWith": "a"}}},
+		"bad count":   {"input": map[string]interface{}{}, "errorCount": -1},
+		"bad format":  {"inputFile": "input.txt", "allow": true},
+	}
+	for name, raw := range invalid {
+		if _, err := parseRuleTestCase(raw, 0, filepath.Join(t.TempDir(), "rule_test.yaml")); err == nil {
+			t.Errorf("%s: expected the test case to be rejected", name)
+		}
+	}
+}
@@ -4,21 +4,32 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
+	"time"
 
-	"github.com/grafana/sobek"
+	"github.com/open-policy-agent/opa/cover"
 	"github.com/open-policy-agent/opa/rego"
 	"gopkg.in/yaml.v3"
 )
 
+// TestAll runs the test cases of every rule in rulesPath.
 func TestAll(rulesPath string) error {
+	return TestAllWithReport(rulesPath, "", 0)
+}
+
+// TestAllWithReport runs the test cases of every rule in rulesPath and, when
+// xunitReport is set, writes one testsuite per rule with a testcase per test
+// case. Every test case runs, also after one has failed. Rego rules whose
+// coverage is below coverageThreshold percent fail.
+func TestAllWithReport(rulesPath string, xunitReport string, coverageThreshold float64) error {
 
 	allRules, err := ReadRulesMetadata(rulesPath)
 
 	if err != nil {
 		return err
 	}
+	if err := loadRulesData(rulesPath); err != nil {
+		return err
+	}
 
 	// Check for duplicate rule numbers
 	ruleNumberMap := make(map[string][]string)
@@ -44,239 +55,228 @@ func TestAll(rulesPath string) error {
 		return fmt.Errorf("found duplicate rule numbers")
 	}
 
+	testsuites := make([]Testsuite, 0, len(allRules))
 	var failed int
 	var firstErr error
 	for _, rule := range allRules {
-		if err := runTestCases(rule); err != nil {
+		testsuite, err := runTestCases(rule, coverageThreshold)
+		if testsuite != nil {
+			testsuites = append(testsuites, *testsuite)
+		}
+		if err != nil {
 			failed++
 			if firstErr == nil {
 				firstErr = err
 			}
 		}
 	}
+	if err := writeReports(testsuites, nil, "", xunitReport, "", ""); err != nil {
+		return err
+	}
 	if failed > 0 {
 		return fmt.Errorf("%d rule test(s) failed: %w", failed, firstErr)
 	}
 	return nil
 }
 
-func runTestCases(rule Rule) error {
-	log.Infof(">> %s", rule.Path)
-	if rule.Language == LanguageJavascript {
-		if err := runJavaScriptTestCases(rule); err != nil {
-			log.Errorf("Failed: %v", err)
-			return err
-		}
-		return nil
-	}
-	if rule.Language == LanguageTypescript {
-		if err := runTypescriptTestCases(rule); err != nil {
-			log.Errorf("Failed: %v", err)
-			return err
-		}
-		return nil
-	}
+// runTestCases runs every test case of a rule and returns them as a
+// testsuite. Rego rules also run the test_ rules of their _test.rego module,
+// and the testsuite records the coverage of the rule module, which fails the
+// rule when it is below coverageThreshold. The error describes the first case
+// that did not pass.
+func runTestCases(rule Rule, coverageThreshold float64) (*Testsuite, error) {
+	var coverage *cover.Cover
 	if rule.Language == LanguageRego {
-		if err := runRegoTestCases(rule); err != nil {
-			log.Errorf("Failed: %v", err)
-			return err
-		}
-		return nil
+		coverage = cover.New()
 	}
-
-	log.Warnf("Skipped unsupported rule %s.", rule.Path)
-	return nil
-}
-
-func runJavaScriptTestCases(rule Rule) error {
-
-	ruleContent, err := os.ReadFile(rule.Path)
-	if err != nil {
-		return err
+	evaluate := ruleTestEvaluator(rule, coverage)
+	if evaluate == nil {
+		log.Warnf("Skipped unsupported rule %s.", rule.Path)
+		return nil, nil
 	}
+	log.Infof(">> %s", rule.Path)
 
-	testFilePath := strings.Replace(rule.Path, ".js", "_test.yaml", 1)
-	testCases, err := readTestCases(testFilePath)
-	if err != nil {
-		return err
+	testsuite := &Testsuite{Name: rule.Path, Testcases: make([]Testcase, 0)}
+	var firstErr error
+	record := func(testcase Testcase) {
+		if err := testsuite.addRuleTestcase(testcase); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
 
-	for _, testCase := range testCases {
-		var input map[string]interface{}
-		var allow bool
-
-		// Handle different map types based on YAML parser
-		switch tcMap := testCase.(type) {
-		case map[interface{}]interface{}:
-			// For yaml.v2
-			input = convertToStringKeyMap(tcMap["input"].(map[interface{}]interface{}))
-			allow = tcMap["allow"].(bool)
-		case map[string]interface{}:
-			// For yaml.v3
-			inputVal := tcMap["input"]
-			switch inputMap := inputVal.(type) {
-			case map[interface{}]interface{}:
-				input = convertToStringKeyMap(inputMap)
-			case map[string]interface{}:
-				input = inputMap
-			default:
-				return fmt.Errorf("unexpected input type: %T", inputVal)
-			}
-			allow = tcMap["allow"].(bool)
-		default:
-			return fmt.Errorf("unexpected testCase type: %T", testCase)
+	testFilePath := ruleTestFilePath(rule)
+	nativeTestPath := ""
+	if rule.Language == LanguageRego {
+		if _, err := os.Stat(regoTestFilePath(rule)); err == nil {
+			nativeTestPath = regoTestFilePath(rule)
 		}
-
-		// Use the directory containing the rule file as the working directory
-		workingDirectory := filepath.Dir(rule.Path)
-		vm := setupJavascriptVM(workingDirectory, workingDirectory)
-		_, err = vm.RunString(string(ruleContent))
+	}
+	// A Rego rule may be tested by its _test.rego module alone.
+	if _, err := os.Stat(testFilePath); err == nil || nativeTestPath == "" {
+		testCases, err := readTestCases(testFilePath)
 		if err != nil {
-			panic(err)
+			record(Testcase{
+				Name:  testFilePath,
+				Error: &RuleError{Message: err.Error(), Type: ruleTestFileError},
+			})
 		}
-
-		ruleFunction, ok := sobek.AssertFunction(vm.Get("rule"))
-		if !ok {
-			panic("rule(...) function not found")
+		for i, raw := range testCases {
+			record(runTestCase(rule, evaluate, raw, i, testFilePath))
+		}
+	}
+	if nativeTestPath != "" {
+		for _, testcase := range runRegoNativeTests(rule, nativeTestPath, coverage) {
+			record(testcase)
 		}
+	}
 
-		res, err := ruleFunction(sobek.Undefined(), vm.ToValue(input))
+	if coverage != nil {
+		percentage, err := regoCoverage(rule, coverage)
 		if err != nil {
-			panic(err)
+			log.Warnf("Failed to compute the coverage of %s: %v", rule.Path, err)
+			return testsuite, firstErr
 		}
-
-		rs := res.Export().(map[string]interface{})
-
-		result := rs["allow"].(bool)
-		errors := rs["errors"].([]interface{})
-
-		// Get the test case name
-		var name string
-		switch tcMap := testCase.(type) {
-		case map[interface{}]interface{}:
-			if n, ok := tcMap["name"].(string); ok {
-				name = n
-			} else {
-				name = "unnamed test"
-			}
-		case map[string]interface{}:
-			if n, ok := tcMap["name"].(string); ok {
-				name = n
-			} else {
-				name = "unnamed test"
-			}
-		}
-
-		if result != allow {
-			for _, error := range errors {
-				log.Errorf("Error: %s", error)
-			}
-			return fmt.Errorf("FAIL %s: Expected %v, got: %v", name, allow, result)
-		} else {
-			log.Infof("PASS  %s ", name)
+		testsuite.Coverage = &percentage
+		log.Infof("COVERAGE %.1f%%", percentage)
+		if percentage < coverageThreshold {
+			record(Testcase{
+				Name: "coverage",
+				Failure: &Failure{
+					Message: fmt.Sprintf("coverage of %.1f%% is below the threshold of %.1f%%", percentage, coverageThreshold),
+					Type:    "CoverageError",
+				},
+			})
 		}
 	}
-
-	return nil
+	return testsuite, firstErr
 }
 
-func runRegoTestCases(rule Rule) error {
-
-	packageName := getPackageName(rule.Path)
-	queryString := fmt.Sprintf("data.%s.allow", packageName)
-	testFilePath := strings.Replace(rule.Path, ".rego", "_test.yaml", 1)
+// addRuleTestcase adds the result of a rule test case to the testsuite and
+// logs it. It returns an error when the case did not pass.
+func (testsuite *Testsuite) addRuleTestcase(testcase Testcase) error {
+	testsuite.Tests++
+	testsuite.Time += testcase.Time
+	testsuite.Testcases = append(testsuite.Testcases, testcase)
+
+	var message string
+	switch {
+	case testcase.Failure != nil:
+		testsuite.Failures++
+		message = testcase.Failure.Message
+	case testcase.Error != nil:
+		testsuite.Errors++
+		message = testcase.Error.Message
+	case testcase.Skipped != nil:
+		testsuite.Skipped++
+		log.Infof("SKIP  %s", testcase.Name)
+		return nil
+	default:
+		log.Infof("PASS  %s", testcase.Name)
+		return nil
+	}
+	log.Errorf("FAIL %s: %s", testcase.Name, message)
+	return fmt.Errorf("FAIL %s: %s", testcase.Name, message)
+}
 
-	testCases, err := readTestCases(testFilePath)
+func runTestCase(rule Rule, evaluate ruleTestEvaluatorFunc, raw interface{}, index int, testFilePath string) Testcase {
+	testCase, err := parseRuleTestCase(raw, index, testFilePath)
 	if err != nil {
-		return err
+		return Testcase{
+			Name:  ruleTestCaseName(raw, index),
+			Error: &RuleError{Message: fmt.Sprintf("Invalid test case in %s: %v", testFilePath, err), Type: ruleTestFileError},
+		}
 	}
 
-	// Read and pre-process rego content once for all test cases
-	regoFile, err := os.ReadFile(rule.Path)
+	startTime := time.Now()
+	allow, errors, err := evaluate(testCase.input, testCaseSettings(rule, testCase.raw))
+	testcase := Testcase{
+		Name: testCase.name,
+		Time: float64(time.Since(startTime).Nanoseconds()) / 1e9, // convert to seconds
+	}
 	if err != nil {
-		return err
+		testcase.Error = newRuleError(rule.Path, testCase.name, err)
+		return testcase
 	}
-	// Pre-process rego content to quote rulenumber in metadata
-	// This prevents YAML 1.1 octal interpretation of values like "002_0002"
-	regoContent := quoteRegoMetadataRulenumber(string(regoFile))
-
-	for _, testCase := range testCases {
-		var input map[string]interface{}
-		var allow bool
+	violations := parseViolations(errors)
+	if mismatches := testCase.check(allow, violations); len(mismatches) > 0 {
+		testcase.Failure = newRuleTestFailure(mismatches, violations)
+		testcase.Violations = violations
+	}
+	return testcase
+}
 
-		// Handle different map types based on YAML parser
-		switch tcMap := testCase.(type) {
-		case map[interface{}]interface{}:
-			// For yaml.v2
-			input = convertToStringKeyMap(tcMap["input"].(map[interface{}]interface{}))
-			allow = tcMap["allow"].(bool)
-		case map[string]interface{}:
-			// For yaml.v3
-			inputVal := tcMap["input"]
-			switch inputMap := inputVal.(type) {
-			case map[interface{}]interface{}:
-				input = convertToStringKeyMap(inputMap)
-			case map[string]interface{}:
-				input = inputMap
-			default:
-				return fmt.Errorf("unexpected input type: %T", inputVal)
-			}
-			allow = tcMap["allow"].(bool)
-		default:
-			return fmt.Errorf("unexpected testCase type: %T", testCase)
+// ruleTestEvaluatorFunc evaluates a rule on the input of a test case and
+// returns its allow and errors.
+type ruleTestEvaluatorFunc func(input map[string]interface{}, settings map[string]interface{}) (bool, []interface{}, error)
+
+// ruleTestEvaluator returns the evaluator for the language of a rule, or nil
+// when the language has no test cases. Rego evaluations are recorded in
+// coverage.
+func ruleTestEvaluator(rule Rule, coverage *cover.Cover) ruleTestEvaluatorFunc {
+	switch rule.Language {
+	case LanguageRego:
+		return func(input map[string]interface{}, settings map[string]interface{}) (bool, []interface{}, error) {
+			return evalRegoTestCase(rule, input, settings, coverage)
 		}
-
-		ctx := context.Background()
-
-		r := rego.New(
-			rego.Query(queryString),
-			rego.Module(rule.Path, regoContent),
-			rego.Input(input),
-			rego.Trace(true),
-		)
-
-		rs, err := r.Eval(ctx)
-		if err != nil {
-			log.Fatal(err)
-			return err
+	case LanguageJavascript:
+		return func(input map[string]interface{}, settings map[string]interface{}) (bool, []interface{}, error) {
+			compiled, err := prepareJavascriptRule(rule.Path, settings)
+			if err != nil {
+				return false, nil, newRuleEvalError(ruleErrorLoad, err)
+			}
+			return compiled.evalTestCase(input)
 		}
-
-		log.Debugf("Result: %v", rs)
-
-		result := rs[0].Expressions[0].Value.(bool)
-
-		// Get the test case name
-		var name string
-		switch tcMap := testCase.(type) {
-		case map[interface{}]interface{}:
-			if n, ok := tcMap["name"].(string); ok {
-				name = n
-			} else {
-				name = "unnamed test"
+	case LanguageTypescript:
+		return func(input map[string]interface{}, settings map[string]interface{}) (bool, []interface{}, error) {
+			compiled, err := prepareTypescriptRule(rule.Path, settings)
+			if err != nil {
+				return false, nil, newRuleEvalError(ruleErrorLoad, err)
 			}
-		case map[string]interface{}:
-			if n, ok := tcMap["name"].(string); ok {
-				name = n
-			} else {
-				name = "unnamed test"
+			return compiled.evalTestCase(input)
+		}
+	case LanguageYaml:
+		return func(input map[string]interface{}, settings map[string]interface{}) (bool, []interface{}, error) {
+			compiled, err := prepareYamlRule(rule.Path, settings)
+			if err != nil {
+				return false, nil, newRuleEvalError(ruleErrorLoad, err)
 			}
+			allow, errors := compiled.evaluate(input)
+			return allow, errors, nil
 		}
-
-		if result != allow {
-			log.Errorf("FAIL %s: Expected: %v, got: %v", name, allow, result)
-			errors := rs[0].Expressions[1].Value.([]interface{})
-			for _, error := range errors {
-				log.Errorf("Error: %s", error)
+	case LanguageJSONSchema:
+		return func(input map[string]interface{}, settings map[string]interface{}) (bool, []interface{}, error) {
+			prepared, err := prepareSchemaRule(rule.Path)
+			if err != nil {
+				return false, nil, newRuleEvalError(ruleErrorLoad, err)
 			}
-			return fmt.Errorf("FAIL %s: Expected: %v, got: %v", name, allow, result)
+			return prepared.validate(context.Background(), input, rego.EvalInput(input))
 		}
-
-		log.Infof("PASS  %s", name)
 	}
-
 	return nil
 }
 
+// evalRegoTestCase queries the package of a Rego rule, like lint does, so
+// that allow and errors are both read.
+func evalRegoTestCase(rule Rule, input map[string]interface{}, settings map[string]interface{}, coverage *cover.Cover) (bool, []interface{}, error) {
+	queryString := "data." + rule.PackageName
+	prepared, err := prepareRegoRule(rule.Path, queryString, settings)
+	if err != nil {
+		return false, nil, newRuleEvalError(ruleErrorLoad, err)
+	}
+	evalOptions := []rego.EvalOption{rego.EvalInput(input)}
+	if coverage != nil {
+		evalOptions = append(evalOptions, rego.EvalQueryTracer(coverage))
+	}
+	rs, err := prepared.query.Eval(context.Background(), evalOptions...)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return false, nil, newRuleEvalError(ruleErrorContract, fmt.Errorf("query %s returned no result", queryString))
+	}
+	return parseRuleResult(rs[0].Expressions[0].Value)
+}
+
 // convertToStringKeyMap converts a map[interface{}]interface{} to map[string]interface{}
 func convertToStringKeyMap(m map[interface{}]interface{}) map[string]interface{} {
 	result := make(map[string]interface{})
@@ -310,21 +310,6 @@ func convertSlice(s []interface{}) []interface{} {
 	return result
 }
 
-func getPackageName(rulePath string) string {
-	fileContent, err := os.ReadFile(rulePath)
-	if err != nil {
-		return ""
-	}
-
-	lines := strings.Split(string(fileContent), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "package ") {
-			return strings.TrimSpace(strings.Split(line, " ")[1])
-		}
-	}
-	return ""
-}
-
 func readTestCases(testFilePath string) ([]interface{}, error) {
 
 	testFileContent, err := os.ReadFile(testFilePath)
@@ -344,7 +329,10 @@ func readTestCases(testFilePath string) ([]interface{}, error) {
 		log.Errorf("Failed to decode test file %s: %v", testFilePath, err)
 		return nil, err
 	}
-	testCases := data["TestCases"].([]interface{})
+	testCases, ok := data["TestCases"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("test file %s must contain a TestCases list", testFilePath)
+	}
 
 	return testCases, nil
 }
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rulesDataDirectory is the folder of a rules directory that holds external
+// data files, such as allow-lists, shared by all rules.
+const rulesDataDirectory = "data"
+
+// rulesData holds the external data files of the current run. Rego rules read
+// them as data.mxlint.external and JavaScript and TypeScript rules as
+// mxlint.data, keyed by file name without extension.
+var rulesData = struct {
+	mu   sync.RWMutex
+	data map[string]interface{}
+	hash string
+}{}
+
+func isRulesDataFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// rulesDataFiles returns the data files of the rules directory followed by
+// those of rules.data, which lists files or directories.
+func rulesDataFiles(rulesPath string) ([]string, error) {
+	sources := []string{filepath.Join(rulesPath, rulesDataDirectory)}
+	configured := make([]string, 0)
+	if cfg := getConfig(); cfg != nil {
+		for _, source := range cfg.Rules.Data {
+			if source = strings.TrimSpace(source); source != "" {
+				configured = append(configured, source)
+			}
+		}
+	}
+	sources = append(sources, configured...)
+
+	files := make([]string, 0)
+	for i, source := range sources {
+		info, err := os.Stat(source)
+		if err != nil {
+			// The data directory of the rules is optional; rules.data entries are not.
+			if i == 0 && os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read rules data %s: %w", source, err)
+		}
+		if !info.IsDir() {
+			files = append(files, source)
+			continue
+		}
+		entries, err := os.ReadDir(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules data %s: %w", source, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isRulesDataFile(entry.Name()) {
+				files = append(files, filepath.Join(source, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
+
+// loadRulesData reads the external data files once for a run of lint or
+// test-rules. Values are normalized to JSON types so Rego and JavaScript see
+// the same numbers, lists and objects.
+func loadRulesData(rulesPath string) error {
+	files, err := rulesDataFiles(rulesPath)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{}
+	sources := map[string]string{}
+	hasher := sha256.New()
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if previous, ok := sources[name]; ok {
+			return fmt.Errorf("rules data %q is defined in both %s and %s", name, previous, file)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read rules data %s: %w", file, err)
+		}
+		value, err := parseRulesDataFile(file, content)
+		if err != nil {
+			return err
+		}
+		data[name] = value
+		sources[name] = file
+		fmt.Fprintf(hasher, "%s:%x;", name, sha256.Sum256(content))
+		log.Debugf("Loaded rules data %s from %s", name, file)
+	}
+
+	hash := ""
+	if len(data) > 0 {
+		hash = fmt.Sprintf("%x", hasher.Sum(nil))
+	}
+	setRulesData(data, hash)
+	return nil
+}
+
+func parseRulesDataFile(file string, content []byte) (interface{}, error) {
+	var value interface{}
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		if err := json.Unmarshal(content, &value); err != nil {
+			return nil, fmt.Errorf("failed to parse rules data %s: %w", file, err)
+		}
+		return value, nil
+	}
+	if err := yaml.Unmarshal(content, &value); err != nil {
+		return nil, fmt.Errorf("failed to parse rules data %s: %w", file, err)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rules data %s: %w", file, err)
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, fmt.Errorf("failed to parse rules data %s: %w", file, err)
+	}
+	return normalized, nil
+}
+
+func setRulesData(data map[string]interface{}, hash string) {
+	rulesData.mu.Lock()
+	defer rulesData.mu.Unlock()
+	rulesData.data = data
+	rulesData.hash = hash
+}
+
+// getRulesData returns the external data of the current run. Callers must not
+// modify it; JavaScript rules get a copy.
+func getRulesData() map[string]interface{} {
+	rulesData.mu.RLock()
+	defer rulesData.mu.RUnlock()
+	if rulesData.data == nil {
+		return map[string]interface{}{}
+	}
+	return rulesData.data
+}
+
+// rulesDataHash returns the content hash of the external data files, or an
+// empty string when there are none.
+func rulesDataHash() string {
+	rulesData.mu.RLock()
+	defer rulesData.mu.RUnlock()
+	return rulesData.hash
+}
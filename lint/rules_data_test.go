@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRulesDataFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("Failed to create data directory: %v", err)
+	}
+	if err := writeTestFile(dir, name, content); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	return filepath.Join(dir, name)
+}
+
+func TestLoadRulesData(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+		setRulesData(nil, "")
+	})
+
+	rulesDir := t.TempDir()
+	dataDir := filepath.Join(rulesDir, rulesDataDirectory)
+	writeRulesDataFile(t, dataDir, "approved_modules.yaml", "- Administration\n- Atlas_Core\n")
+	writeRulesDataFile(t, dataDir, "limits.json", `{"maxEntities": 20}`)
+	writeRulesDataFile(t, dataDir, "README.md", "not data")
+	extra := writeRulesDataFile(t, t.TempDir(), "forbidden_actions.yml", "names:\n  - JA_Execute\n")
+	SetConfig(&Config{Rules: ConfigRulesSpec{Data: []string{extra}}})
+
+	if err := loadRulesData(rulesDir); err != nil {
+		t.Fatalf("Failed to load rules data: %v", err)
+	}
+	data := getRulesData()
+	if len(data) != 3 {
+		t.Fatalf("expected three data files, got %v", data)
+	}
+	if modules, ok := data["approved_modules"].([]interface{}); !ok || len(modules) != 2 || modules[0] != "Administration" {
+		t.Fatalf("unexpected approved_modules: %v", data["approved_modules"])
+	}
+	if limits, ok := data["limits"].(map[string]interface{}); !ok || limits["maxEntities"] != 20.0 {
+		t.Fatalf("unexpected limits: %v", data["limits"])
+	}
+	if _, ok := data["forbidden_actions"].(map[string]interface{}); !ok {
+		t.Fatalf("expected rules.data file to be loaded, got %v", data)
+	}
+
+	hash := rulesDataHash()
+	configHash := computeCacheConfigHash()
+	writeRulesDataFile(t, dataDir, "limits.json", `{"maxEntities": 30}`)
+	if err := loadRulesData(rulesDir); err != nil {
+		t.Fatalf("Failed to load rules data: %v", err)
+	}
+	if rulesDataHash() == hash || computeCacheConfigHash() == configHash {
+		t.Fatal("expected changed data to change the cache config hash")
+	}
+
+	writeRulesDataFile(t, filepath.Dir(extra), "limits.yaml", "maxEntities: 10\n")
+	SetConfig(&Config{Rules: ConfigRulesSpec{Data: []string{filepath.Dir(extra)}}})
+	if err := loadRulesData(rulesDir); err == nil || !strings.Contains(err.Error(), `"limits"`) {
+		t.Fatalf("expected duplicate data names to be rejected, got: %v", err)
+	}
+
+	SetConfig(&Config{Rules: ConfigRulesSpec{Data: []string{filepath.Join(rulesDir, "missing.yaml")}}})
+	if err := loadRulesData(rulesDir); err == nil {
+		t.Fatal("expected a missing rules.data entry to be rejected")
+	}
+}
+
+func TestEvalAll_RulesData(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+		setRulesData(nil, "")
+	})
+	SetConfig(&Config{})
+
+	rulesDir := t.TempDir()
+	writeRulesDataFile(t, filepath.Join(rulesDir, rulesDataDirectory), "approved_modules.yaml", "- Administration\n")
+	// A data file is never read as a rule, even when it looks like one.
+	writeRulesDataFile(t, filepath.Join(rulesDir, rulesDataDirectory), "assertions.yaml", "assertions: []\n")
+	files := map[string]string{
+		"001_0001_approved.rego": `# METADATA
+# title: Approved modules
+# description: Modules must be approved
+# custom:
+#   rulenumber: "001_0001"
+#   input: ".*Module.yaml"
+package test.approved
+
+import rego.v1
+
+default allow := false
+
+allow if input.Name in data.mxlint.external.approved_modules
+
+errors := [sprintf("Module %s is not approved", [input.Name])] if not allow else := []
+`,
+		"001_0002_approved.js": `const metadata = {
+    title: "Approved modules",
+    description: "Modules must be approved",
+    custom: { rulenumber: "001_0002", input: ".*Module.yaml" }
+};
+
+function rule(input) {
+    const approved = mxlint.data.approved_modules.includes(input.Name);
+    mxlint.data.approved_modules.push(input.Name);
+    return { allow: approved, errors: approved ? [] : ["Module " + input.Name + " is not approved"] };
+}
+`,
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+
+	modelDir := t.TempDir()
+	for _, name := range []string{"Administration", "Unknown"} {
+		if err := writeTestFile(modelDir, name+"Module.yaml", "Name: "+name+"\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+
+	rules, testsuites, err := evalAll(rulesDir, modelDir, false, false, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected only the two rules, got %+v", rules)
+	}
+	for _, testsuite := range testsuites {
+		if testsuite.Errors != 0 || testsuite.Failures != 1 {
+			t.Fatalf("%s: expected only UnknownModule.yaml to fail, got %+v", filepath.Base(testsuite.Name), testsuite.Testcases)
+		}
+		for _, testcase := range testsuite.Testcases {
+			if (testcase.Failure != nil) != (testcase.Name == "UnknownModule.yaml") {
+				t.Fatalf("%s: unexpected result for %s: %+v", filepath.Base(testsuite.Name), testcase.Name, testcase)
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/evanw/esbuild/pkg/api"
+)
+
+// rulesLibraryDirectory is the folder of a rules directory that holds helper
+// modules shared by JavaScript and TypeScript rules. Rules import them with
+// a relative path or with a path starting with lib/.
+const rulesLibraryDirectory = "lib"
+
+// rulesRoot is the rules directory of the current run. Imports of a rule in
+// it must stay within it.
+var rulesRoot = struct {
+	mu   sync.RWMutex
+	path string
+}{}
+
+func setRulesRoot(rulesPath string) {
+	rulesRoot.mu.Lock()
+	defer rulesRoot.mu.Unlock()
+	rulesRoot.path = rulesPath
+}
+
+// ruleImportRoot returns the directory that imports of rulePath are resolved
+// against and sandboxed to: the rules directory of the run, or the directory
+// of the rule when it is outside of it.
+func ruleImportRoot(rulePath string) (string, error) {
+	absRulePath, err := filepath.Abs(rulePath)
+	if err != nil {
+		return "", err
+	}
+	rulesRoot.mu.RLock()
+	configured := rulesRoot.path
+	rulesRoot.mu.RUnlock()
+	if configured != "" {
+		if root, err := filepath.Abs(configured); err == nil && isWithinDirectory(root, absRulePath) {
+			return root, nil
+		}
+	}
+	return filepath.Dir(absRulePath), nil
+}
+
+func isWithinDirectory(root string, path string) bool {
+	relPath, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) && !filepath.IsAbs(relPath)
+}
+
+// resolveRuleImport resolves an import of a rule to a file within root.
+// Extensions and index files are tried in the order of ruleImportCandidates.
+func resolveRuleImport(importPath string, resolveDir string, root string) (string, error) {
+	var target string
+	switch {
+	case strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../"):
+		target = filepath.Join(resolveDir, filepath.FromSlash(importPath))
+	case importPath == rulesLibraryDirectory || strings.HasPrefix(importPath, rulesLibraryDirectory+"/"):
+		target = filepath.Join(root, filepath.FromSlash(importPath))
+	default:
+		return "", fmt.Errorf("import %q is not supported; use a relative path or a path starting with %s/", importPath, rulesLibraryDirectory)
+	}
+	if !isWithinDirectory(root, target) {
+		return "", fmt.Errorf("import %q is outside rules root %q", importPath, root)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range ruleImportCandidates(target) {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		// Symbolic links must not lead out of the rules root either.
+		realPath, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !isWithinDirectory(realRoot, realPath) {
+			return "", fmt.Errorf("import %q is outside rules root %q", importPath, root)
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("import %q not found", importPath)
+}
+
+func ruleImportCandidates(target string) []string {
+	return []string{
+		target,
+		target + ".ts",
+		target + ".js",
+		filepath.Join(target, "index.ts"),
+		filepath.Join(target, "index.js"),
+	}
+}
+
+// ruleBundleExport is appended to a rule before bundling. The bundle runs in
+// a closure, so helpers cannot clash with the rule, and ruleBundleFooter sets
+// the metadata and rule globals that mxlint reads from the exported bindings.
+const ruleBundleExport = `
+export const bindings = {
+	metadata: typeof metadata === "undefined" ? undefined : metadata,
+	rule: typeof rule === "undefined" ? undefined : rule,
+};
+`
+
+const ruleBundleFooter = `var metadata = __mxlintRule.bindings.metadata;
+var rule = __mxlintRule.bindings.rule;`
+
+// ruleBundleCacheEntry is a bundled rule. hash is the content hash of the
+// rule file and imports maps each imported file to its content hash.
+type ruleBundleCacheEntry struct {
+	hash    string
+	code    string
+	imports map[string]string
+}
+
+var ruleBundleCache = struct {
+	mu      sync.RWMutex
+	entries map[string]ruleBundleCacheEntry
+}{
+	entries: make(map[string]ruleBundleCacheEntry),
+}
+
+// importsUnchanged reports whether every imported file still has the recorded
+// content. The set of imports can only change when the rule or one of its
+// imports changes, so this is enough to keep a bundle.
+func (e ruleBundleCacheEntry) importsUnchanged() bool {
+	for path, hash := range e.imports {
+		current, err := computeFileHash(path)
+		if err != nil || current != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// bundleRule resolves the imports of a JavaScript or TypeScript rule and
+// bundles them with the rule into a single script that defines the metadata
+// and rule globals.
+func bundleRule(rulePath string, loader api.Loader) (ruleBundleCacheEntry, error) {
+	content, err := os.ReadFile(rulePath)
+	if err != nil {
+		return ruleBundleCacheEntry{}, fmt.Errorf("failed to read rule %s: %w", rulePath, err)
+	}
+	ruleHash := hashRuleContent(content)
+
+	ruleBundleCache.mu.RLock()
+	cached, found := ruleBundleCache.entries[rulePath]
+	ruleBundleCache.mu.RUnlock()
+	if found && cached.hash == ruleHash && cached.importsUnchanged() {
+		return cached, nil
+	}
+
+	root, err := ruleImportRoot(rulePath)
+	if err != nil {
+		return ruleBundleCacheEntry{}, err
+	}
+	ruleDirectory, err := filepath.Abs(filepath.Dir(rulePath))
+	if err != nil {
+		return ruleBundleCacheEntry{}, err
+	}
+	var importsMu sync.Mutex
+	imports := map[string]string{}
+	sandbox := api.Plugin{
+		Name: "mxlint-rules",
+		Setup: func(build api.PluginBuild) {
+			build.OnResolve(api.OnResolveOptions{Filter: ".*"}, func(args api.OnResolveArgs) (api.OnResolveResult, error) {
+				path, err := resolveRuleImport(args.Path, args.ResolveDir, root)
+				if err != nil {
+					return api.OnResolveResult{}, err
+				}
+				hash, err := computeFileHash(path)
+				if err != nil {
+					return api.OnResolveResult{}, err
+				}
+				importsMu.Lock()
+				imports[path] = hash
+				importsMu.Unlock()
+				return api.OnResolveResult{Path: path}, nil
+			})
+		},
+	}
+
+	result := api.Build(api.BuildOptions{
+		Stdin: &api.StdinOptions{
+			Contents:   string(content) + ruleBundleExport,
+			ResolveDir: ruleDirectory,
+			Sourcefile: rulePath,
+			Loader:     loader,
+		},
+		Bundle:      true,
+		Format:      api.FormatIIFE,
+		GlobalName:  "__mxlintRule",
+		Footer:      map[string]string{"js": ruleBundleFooter},
+		Platform:    api.PlatformNeutral,
+		Target:      api.ES2019,
+		TreeShaking: api.TreeShakingFalse,
+		Plugins:     []api.Plugin{sandbox},
+		LogLevel:    api.LogLevelSilent,
+	})
+	if len(result.Errors) > 0 {
+		return ruleBundleCacheEntry{}, fmt.Errorf("failed to bundle rule %s: %s", rulePath, formatEsbuildErrors(result.Errors))
+	}
+	if len(result.OutputFiles) == 0 {
+		return ruleBundleCacheEntry{}, fmt.Errorf("failed to bundle rule %s: no output", rulePath)
+	}
+
+	entry := ruleBundleCacheEntry{
+		hash:    ruleHash,
+		code:    string(result.OutputFiles[0].Contents),
+		imports: imports,
+	}
+	ruleBundleCache.mu.Lock()
+	ruleBundleCache.entries[rulePath] = entry
+	ruleBundleCache.mu.Unlock()
+	return entry, nil
+}
+
+// bundleJavascriptRule returns the code of a JavaScript rule with its imports.
+func bundleJavascriptRule(rulePath string) (string, error) {
+	entry, err := bundleRule(rulePath, api.LoaderJS)
+	if err != nil {
+		return "", err
+	}
+	return entry.code, nil
+}
+
+// computeRuleHash returns the hash of a rule for the lint cache. For
+// JavaScript and TypeScript rules it covers every imported file, so editing
+// a helper invalidates the results of the rules that import it.
+func computeRuleHash(rulePath string) (string, error) {
+	var loader api.Loader
+	switch strings.ToLower(filepath.Ext(rulePath)) {
+	case ".js":
+		loader = api.LoaderJS
+	case ".ts":
+		loader = api.LoaderTS
+	default:
+		return computeFileHash(rulePath)
+	}
+	entry, err := bundleRule(rulePath, loader)
+	if err != nil {
+		return "", err
+	}
+	if len(entry.imports) == 0 {
+		return entry.hash, nil
+	}
+
+	paths := make([]string, 0, len(entry.imports))
+	for path := range entry.imports {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	// Paths are relative to the rule, so the hash does not depend on where
+	// the rules directory is.
+	ruleDirectory, err := filepath.Abs(filepath.Dir(rulePath))
+	if err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	hasher.Write([]byte(entry.hash))
+	for _, path := range paths {
+		name := path
+		if relPath, err := filepath.Rel(ruleDirectory, path); err == nil {
+			name = relPath
+		}
+		fmt.Fprintf(hasher, ";%s:%s", filepath.ToSlash(name), entry.imports[path])
+	}
+	return fmt.Sprintf("%x", hasher.Sum(nil)), nil
+}
@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLibraryHelpers = `// Declarations named like those of a rule must not replace them.
+const metadata = { helper: true };
+
+function rule() {
+    return { allow: true, errors: [] };
+}
+
+export function isNPE(entity) {
+    return entity.Persistable === false;
+}
+`
+
+const testLibraryRule = `import { isNPE } from "lib/entities";
+
+const metadata = {
+    title: "No non-persistable entities",
+    description: "Entities must be persistable",
+    custom: { rulenumber: "002_0301", input: ".*DomainModel.yaml" }
+};
+
+function rule(input) {
+    const errors = input.Entities.filter(isNPE).map(entity => "Entity " + entity.Name + " is not persistable");
+    return { allow: errors.length === 0, errors };
+}
+`
+
+const testLibraryTypescriptRule = `import { isNPE } from "../lib/entities";
+
+const metadata = {
+    title: "At least one non-persistable entity",
+    description: "Domain models need a non-persistable entity",
+    custom: { rulenumber: "002_0302", input: ".*DomainModel.yaml" }
+};
+
+function rule(input: { Entities: { Name: string }[] }) {
+    const allow = input.Entities.some(isNPE);
+    return { allow, errors: allow ? [] : ["No non-persistable entity"] };
+}
+`
+
+func writeLibraryRules(t *testing.T) string {
+	t.Helper()
+	rulesDir := t.TempDir()
+	for _, dir := range []string{rulesLibraryDirectory, "002_domain_model"} {
+		if err := os.MkdirAll(filepath.Join(rulesDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(rulesLibraryDirectory, "entities.js"):             testLibraryHelpers,
+		filepath.Join("002_domain_model", "002_0301_npe.js"):            testLibraryRule,
+		filepath.Join("002_domain_model", "002_0302_has_npe.ts"):        testLibraryTypescriptRule,
+		filepath.Join("002_domain_model", "002_0301_npe_test.yaml"):     "TestCases:\n- name: allow\n  input:\n    Entities:\n      - Name: Customer\n        Persistable: true\n  allow: true\n- name: no_allow\n  input:\n    Entities:\n      - Name: Filter\n        Persistable: false\n  allow: false\n",
+		filepath.Join("002_domain_model", "002_0302_has_npe_test.yaml"): "TestCases:\n- name: allow\n  input:\n    Entities:\n      - Name: Filter\n        Persistable: false\n  allow: true\n",
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+	return rulesDir
+}
+
+func TestEvalAll_LibraryImports(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+		setRulesRoot("")
+	})
+	SetConfig(&Config{})
+	rulesDir := writeLibraryRules(t)
+
+	modelDir := t.TempDir()
+	content := "Entities:\n  - Name: Customer\n    Persistable: true\n  - Name: Filter\n    Persistable: false\n"
+	if err := writeTestFile(modelDir, "DomainModel.yaml", content); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	rules, testsuites, err := evalAll(rulesDir, modelDir, false, false, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected the helpers in lib/ not to be read as rules, got %+v", rules)
+	}
+	for _, testsuite := range testsuites {
+		if testsuite.Errors != 0 || testsuite.Tests != 1 {
+			t.Fatalf("%s: expected one evaluated testcase, got %+v", filepath.Base(testsuite.Name), testsuite.Testcases)
+		}
+		expectFailure := strings.HasSuffix(testsuite.Name, "002_0301_npe.js")
+		if (testsuite.Failures == 1) != expectFailure {
+			t.Fatalf("%s: unexpected result %+v", filepath.Base(testsuite.Name), testsuite.Testcases)
+		}
+	}
+
+	if err := TestAll(rulesDir); err != nil {
+		t.Fatalf("Expected rule tests to pass: %v", err)
+	}
+}
+
+func TestCacheKeyIncludesImports(t *testing.T) {
+	rulesDir := writeLibraryRules(t)
+	rulePath := filepath.Join(rulesDir, "002_domain_model", "002_0301_npe.js")
+	inputPath := filepath.Join(rulesDir, "input.yaml")
+	if err := writeTestFile(rulesDir, "input.yaml", "Entities: []\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	setRulesRoot(rulesDir)
+	t.Cleanup(func() {
+		setRulesRoot("")
+	})
+	first, err := createCacheKey(rulePath, inputPath)
+	if err != nil {
+		t.Fatalf("Failed to create cache key: %v", err)
+	}
+	ruleOnly, err := computeFileHash(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to hash rule: %v", err)
+	}
+	if first.RuleHash == ruleOnly {
+		t.Fatal("expected the rule hash to cover the imported helpers")
+	}
+
+	helpers := strings.Replace(testLibraryHelpers, "=== false", "!== true", 1)
+	if err := writeTestFile(filepath.Join(rulesDir, rulesLibraryDirectory), "entities.js", helpers); err != nil {
+		t.Fatalf("Failed to update helpers: %v", err)
+	}
+	second, err := createCacheKey(rulePath, inputPath)
+	if err != nil {
+		t.Fatalf("Failed to create cache key: %v", err)
+	}
+	if first.RuleHash == second.RuleHash {
+		t.Fatal("expected editing a helper to change the rule hash")
+	}
+	code, err := bundleJavascriptRule(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to bundle rule: %v", err)
+	}
+	if !strings.Contains(code, "!== true") {
+		t.Fatalf("expected the bundle to be rebuilt after a helper changed, got:\n%s", code)
+	}
+}
+
+func TestBundleRule_ImportsAreSandboxed(t *testing.T) {
+	t.Cleanup(func() {
+		setRulesRoot("")
+	})
+	outside := t.TempDir()
+	if err := writeTestFile(outside, "secret.js", "export const secret = 1;\n"); err != nil {
+		t.Fatalf("Failed to write helper: %v", err)
+	}
+	rulesDir := t.TempDir()
+	setRulesRoot(rulesDir)
+
+	imports := map[string]string{
+		"001_0001_outside.js": "../" + filepath.Base(outside) + "/secret",
+		"001_0002_package.js": "lodash",
+		"001_0003_missing.js": "lib/missing",
+	}
+	if err := os.Symlink(outside, filepath.Join(rulesDir, rulesLibraryDirectory)); err == nil {
+		imports["001_0004_symlink.js"] = "lib/secret"
+	}
+	for name, importPath := range imports {
+		content := `import { secret } from "` + importPath + `";
+function rule(input) { return { allow: secret === 1, errors: [] }; }
+`
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+		if _, err := bundleJavascriptRule(filepath.Join(rulesDir, name)); err == nil {
+			t.Fatalf("%s: expected import %q to be rejected", name, importPath)
+		}
+	}
+}
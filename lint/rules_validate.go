@@ -0,0 +1,183 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	ValidationError   = "error"
+	ValidationWarning = "warning"
+)
+
+// RuleFinding is a problem with a rule reported by ValidateRules.
+type RuleFinding struct {
+	Path       string `json:"path"`
+	RuleNumber string `json:"ruleNumber,omitempty"`
+	Check      string `json:"check"`
+	Level      string `json:"level"`
+	Message    string `json:"message"`
+}
+
+// RulesValidation is the result of validating a rules directory.
+type RulesValidation struct {
+	Rules    int           `json:"rules"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Findings []RuleFinding `json:"findings"`
+}
+
+func (v *RulesValidation) add(rule Rule, check string, level string, format string, args ...interface{}) {
+	v.Findings = append(v.Findings, RuleFinding{
+		Path:       rule.Path,
+		RuleNumber: rule.RuleNumber,
+		Check:      check,
+		Level:      level,
+		Message:    fmt.Sprintf(format, args...),
+	})
+	if level == ValidationError {
+		v.Errors++
+	} else {
+		v.Warnings++
+	}
+}
+
+// ValidateRules checks the metadata, input pattern, package, rule number and
+// test cases of every rule in rulesPath without running them. Rules whose
+// input matches no document in modelSourcePath are reported as warnings; the
+// check is skipped when modelSourcePath is empty or does not exist.
+func ValidateRules(rulesPath string, modelSourcePath string) (*RulesValidation, error) {
+	validation := &RulesValidation{Findings: make([]RuleFinding, 0)}
+	rules := make([]Rule, 0)
+	walkErr := walkRuleFiles(rulesPath, func(path string, kind string, parse ruleMetadataParser) error {
+		validation.Rules++
+		rule, err := parse(path)
+		if err != nil {
+			validation.add(Rule{Path: path}, "metadata", ValidationError, "failed to parse %s rule metadata: %s", kind, err)
+			return nil
+		}
+		if rule.Language == LanguageRego {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			// parseRuleMetadata_Rego carries on with empty metadata.
+			if _, _, err := readRegoMetadata(string(content)); err != nil {
+				validation.add(*rule, "metadata", ValidationError, "invalid METADATA YAML: %s", err)
+			}
+		}
+		rules = append(rules, *rule)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	var index *modelIndex
+	if modelSourcePath != "" {
+		if _, err := os.Stat(modelSourcePath); err == nil {
+			if index, err = buildModelIndex(modelSourcePath); err != nil {
+				return nil, err
+			}
+		} else {
+			log.Warnf("Modelsource %s not found; skipping the input match check", modelSourcePath)
+		}
+	}
+
+	numbers := map[string][]string{}
+	for _, rule := range rules {
+		validateRule(validation, rule, index)
+		if rule.RuleNumber != "" {
+			numbers[rule.RuleNumber] = append(numbers[rule.RuleNumber], rule.Path)
+		}
+	}
+	for _, rule := range rules {
+		if paths := numbers[rule.RuleNumber]; len(paths) > 1 {
+			others := make([]string, 0, len(paths)-1)
+			for _, path := range paths {
+				if path != rule.Path {
+					others = append(others, path)
+				}
+			}
+			validation.add(rule, "rulenumber", ValidationError, "rule number %s is also used by %s", rule.RuleNumber, strings.Join(others, ", "))
+		}
+	}
+
+	sort.SliceStable(validation.Findings, func(i, j int) bool {
+		return validation.Findings[i].Path < validation.Findings[j].Path
+	})
+	return validation, nil
+}
+
+// validateRule adds the findings of the checks on a single rule.
+func validateRule(validation *RulesValidation, rule Rule, index *modelIndex) {
+	required := []struct {
+		field string
+		value string
+	}{
+		{"title", rule.Title},
+		{"description", rule.Description},
+		{"category", rule.Category},
+		{"severity", rule.Severity},
+		{"rulenumber", rule.RuleNumber},
+		{"input", rule.Pattern},
+	}
+	for _, metadata := range required {
+		if strings.TrimSpace(metadata.value) == "" {
+			validation.add(rule, "metadata", ValidationError, "%s is required", metadata.field)
+		}
+	}
+
+	if rule.Severity != "" {
+		if err := validateSeverity(rule.Severity); err != nil {
+			validation.add(rule, "severity", ValidationError, "%s", err)
+		}
+	}
+	if rule.RuleNumber != "" && !ruleNumberFormat.MatchString(rule.RuleNumber) {
+		validation.add(rule, "rulenumber", ValidationError, "rule number %q does not match the NNN_NNNN format", rule.RuleNumber)
+	}
+	if rule.Language == LanguageRego && rule.PackageName == "" {
+		validation.add(rule, "package", ValidationError, "package declaration is missing")
+	}
+
+	if rule.Pattern != "" {
+		re, err := rulePatternRegexp(rule.Pattern)
+		if err != nil {
+			validation.add(rule, "input", ValidationError, "%s", err)
+		} else if index != nil && !index.matches(re) {
+			validation.add(rule, "input", ValidationWarning, "input %s matches no document in %s", rule.Pattern, index.root)
+		}
+	}
+
+	// Native Rego tests replace the _test.yaml file of a Rego rule.
+	if !fileExists(ruleTestFilePath(rule)) && (rule.Language != LanguageRego || !fileExists(regoTestFilePath(rule))) {
+		validation.add(rule, "tests", ValidationError, "test cases %s not found", ruleTestFilePath(rule))
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// WriteText writes a line per finding followed by a summary.
+func (v *RulesValidation) WriteText(w io.Writer) error {
+	for _, finding := range v.Findings {
+		if _, err := fmt.Fprintf(w, "%s: %s [%s] %s\n", strings.ToUpper(finding.Level), finding.Path, finding.Check, finding.Message); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d rule(s) checked: %d error(s), %d warning(s)\n", v.Rules, v.Errors, v.Warnings)
+	return err
+}
+
+// WriteJSON writes the validation as an indented JSON object.
+func (v *RulesValidation) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,130 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidateRules(t *testing.T) {
+	t.Cleanup(func() {
+		setRulesRoot("")
+	})
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "DomainModels$DomainModel.yaml", "Entities: []\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	rulesDir := t.TempDir()
+	files := map[string]string{
+		"002_0401_documentation.rego":      testAssertionRego,
+		"002_0401_documentation_test.yaml": testAssertionCases,
+		// Broken YAML in the METADATA block is only a warning at lint time.
+		"002_0402_broken.rego":       "# METADATA\n# title: [Broken\n# custom:\n#  rulenumber: 002_0402\npackage app.mendix.test.broken\nimport rego.v1\ndefault allow := true\n",
+		"002_0403_invalid.rego":      "# METADATA\n# title: Invalid\n# description: Invalid\n# custom:\n#  category: Model\n#  severity: CRITICAL\n#  rulenumber: \"2_403\"\n#  input: (\n\nimport rego.v1\ndefault allow := true\n",
+		"002_0403_invalid_test.rego": "package app.mendix.test.invalid_test\n",
+		// Duplicates the number of the documentation rule, without test cases.
+		"002_0404_duplicate.js":         strings.Replace(testAssertionJavascript, "002_0402", "002_0401", 1),
+		"002_0405_microflows.js":        strings.Replace(testAssertionJavascript, `rulenumber: "002_0402", input: ".*DomainModel.yaml"`, `rulenumber: "002_0405", input: ".*\\$Microflow\\.yaml", category: "Model", severity: "LOW"`, 1),
+		"002_0405_microflows_test.yaml": testAssertionCases,
+		"002_0406_no_metadata.js":       "function rule(input) { return { allow: true, errors: [] }; }\n",
+	}
+	for name, content := range files {
+		if err := writeTestFile(rulesDir, name, content); err != nil {
+			t.Fatalf("Failed to write rule file: %v", err)
+		}
+	}
+
+	validation, err := ValidateRules(rulesDir, modelDir)
+	if err != nil {
+		t.Fatalf("Failed to validate rules: %v", err)
+	}
+	findings := map[string][]string{}
+	for _, finding := range validation.Findings {
+		name := filepath.Base(finding.Path)
+		findings[name] = append(findings[name], finding.Level+" "+finding.Check+": "+finding.Message)
+	}
+	expected := map[string][]string{
+		"002_0401_documentation.rego": {
+			"error metadata: category is required",
+			"error metadata: severity is required",
+			"error rulenumber: rule number 002_0401 is also used by " + filepath.Join(rulesDir, "002_0404_duplicate.js"),
+		},
+		"002_0402_broken.rego": {
+			"error metadata: invalid METADATA YAML: yaml: line 1: did not find expected ',' or ']'",
+			"error metadata: description is required",
+			"error metadata: title is required",
+			"error metadata: category is required",
+			"error metadata: severity is required",
+			"error metadata: rulenumber is required",
+			"error metadata: input is required",
+			"error tests: test cases " + filepath.Join(rulesDir, "002_0402_broken_test.yaml") + " not found",
+		},
+		"002_0403_invalid.rego": {
+			`error severity: unknown severity "CRITICAL" (expected one of LOW, MEDIUM, HIGH)`,
+			`error rulenumber: rule number "2_403" does not match the NNN_NNNN format`,
+			"error package: package declaration is missing",
+			"error input: invalid input pattern (: error parsing regexp: missing closing ): `(`",
+		},
+		"002_0404_duplicate.js": {
+			"error metadata: category is required",
+			"error metadata: severity is required",
+			"error tests: test cases " + filepath.Join(rulesDir, "002_0404_duplicate_test.yaml") + " not found",
+			"error rulenumber: rule number 002_0401 is also used by " + filepath.Join(rulesDir, "002_0401_documentation.rego"),
+		},
+		"002_0405_microflows.js": {
+			"warning input: input .*\\$Microflow\\.yaml matches no document in " + modelDir,
+		},
+		"002_0406_no_metadata.js": {
+			"error metadata: failed to parse javascript rule metadata: metadata object not defined",
+		},
+	}
+	for name, messages := range expected {
+		got := findings[name]
+		sort.Strings(got)
+		sort.Strings(messages)
+		if strings.Join(got, "\n") != strings.Join(messages, "\n") {
+			t.Errorf("%s: expected findings\n%s\ngot\n%s", name, strings.Join(messages, "\n"), strings.Join(got, "\n"))
+		}
+	}
+	if len(findings) != len(expected) {
+		t.Errorf("expected findings for %d rules, got %v", len(expected), findings)
+	}
+	if validation.Rules != 6 || validation.Warnings != 1 || validation.Errors != len(validation.Findings)-1 {
+		t.Fatalf("unexpected summary %d rules, %d errors, %d warnings", validation.Rules, validation.Errors, validation.Warnings)
+	}
+
+	var output bytes.Buffer
+	if err := validation.WriteJSON(&output); err != nil {
+		t.Fatalf("Failed to write json: %v", err)
+	}
+	var decoded RulesValidation
+	if err := json.Unmarshal(output.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to parse json: %v", err)
+	}
+	if len(decoded.Findings) != len(validation.Findings) || decoded.Errors != validation.Errors {
+		t.Fatalf("expected the json to round trip, got %+v", decoded)
+	}
+}
+
+func TestValidateRules_WithoutModelsource(t *testing.T) {
+	t.Cleanup(func() {
+		setRulesRoot("")
+	})
+	validation, err := ValidateRules("./../resources/rules", filepath.Join(t.TempDir(), "missing"))
+	if err != nil {
+		t.Fatalf("Failed to validate rules: %v", err)
+	}
+	if validation.Rules == 0 || len(validation.Findings) != 0 {
+		t.Fatalf("expected the bundled rules to be valid, got %+v", validation.Findings)
+	}
+	var output bytes.Buffer
+	if err := validation.WriteText(&output); err != nil {
+		t.Fatalf("Failed to write text: %v", err)
+	}
+	if !strings.HasSuffix(output.String(), "0 error(s), 0 warning(s)\n") {
+		t.Fatalf("unexpected summary %q", output.String())
+	}
+}
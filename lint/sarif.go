@@ -0,0 +1,320 @@
+package lint
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	sarifVersion        = "2.1.0"
+	sarifSchema         = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName       = "mxlint"
+	sarifToolURI        = "https://github.com/mxlint/mxlint-cli"
+	sarifModelsourceURI = "MODELSOURCE"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool               sarifTool                        `json:"tool"`
+	Invocations        []sarifInvocation                `json:"invocations"`
+	OriginalURIBaseIDs map[string]sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+	Results            []sarifResult                    `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level          string                             `json:"level"`
+	Message        sarifMessage                       `json:"message"`
+	Locations      []sarifLocation                    `json:"locations,omitempty"`
+	AssociatedRule *sarifReportingDescriptorReference `json:"associatedRule,omitempty"`
+}
+
+type sarifReportingDescriptorReference struct {
+	ID    string `json:"id"`
+	Index int    `json:"index"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	InformationURI string                     `json:"informationUri"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	ShortDescription     *sarifMessage          `json:"shortDescription,omitempty"`
+	FullDescription      *sarifMessage          `json:"fullDescription,omitempty"`
+	Help                 *sarifMessage          `json:"help,omitempty"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           sarifRuleProperties    `json:"properties"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProperties struct {
+	RuleNumber string `json:"ruleNumber,omitempty"`
+	Category   string `json:"category,omitempty"`
+	Severity   string `json:"severity,omitempty"`
+	Language   string `json:"language,omitempty"`
+	Path       string `json:"path,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// sarifLevel maps a rule severity onto the closest SARIF result level.
+func sarifLevel(severity string) string {
+	switch strings.ToUpper(strings.TrimSpace(severity)) {
+	case "HIGH":
+		return "error"
+	case "LOW":
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func sarifOptionalMessage(text string) *sarifMessage {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	return &sarifMessage{Text: text}
+}
+
+// buildSarifLog converts lint results into a SARIF 2.1.0 log. Testsuites are
+// matched to rules by rule path, which is how evalTestsuites names them.
+func buildSarifLog(testsuites []Testsuite, rules []Rule, modelSourcePath string) sarifLog {
+	descriptors := make([]sarifReportingDescriptor, 0, len(rules))
+	ruleIndexByPath := make(map[string]int, len(rules))
+	for i, rule := range rules {
+		id := rule.RuleNumber
+		if id == "" {
+			id = filepath.ToSlash(rule.Path)
+		}
+		descriptors = append(descriptors, sarifReportingDescriptor{
+			ID:                   id,
+			Name:                 rule.RuleName,
+			ShortDescription:     sarifOptionalMessage(rule.Title),
+			FullDescription:      sarifOptionalMessage(rule.Description),
+			Help:                 sarifOptionalMessage(rule.Remediation),
+			DefaultConfiguration: sarifRuleConfiguration{Level: sarifLevel(rule.Severity)},
+			Properties: sarifRuleProperties{
+				RuleNumber: rule.RuleNumber,
+				Category:   rule.Category,
+				Severity:   rule.Severity,
+				Language:   rule.Language,
+				Path:       filepath.ToSlash(rule.Path),
+			},
+		})
+		ruleIndexByPath[rule.Path] = i
+	}
+
+	results := make([]sarifResult, 0)
+	notifications := make([]sarifNotification, 0)
+	for _, ts := range testsuites {
+		ruleIndex, ok := ruleIndexByPath[ts.Name]
+		if !ok {
+			continue
+		}
+		descriptor := descriptors[ruleIndex]
+		for _, tc := range ts.Testcases {
+			switch {
+			case tc.Failure != nil:
+				results = append(results, sarifTestcaseResults(descriptor, ruleIndex, tc, tc.Failure.Message, false)...)
+			case tc.Warning != nil:
+				results = append(results, sarifTestcaseResults(descriptor, ruleIndex, tc, tc.Warning.Message, true)...)
+			case tc.Error != nil:
+				notifications = append(notifications, sarifNotification{
+					Level:          "error",
+					Message:        sarifMessage{Text: tc.Error.Message},
+					Locations:      []sarifLocation{sarifTestcaseLocation(tc, Violation{})},
+					AssociatedRule: &sarifReportingDescriptorReference{ID: descriptor.ID, Index: ruleIndex},
+				})
+			}
+		}
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           sarifToolName,
+			InformationURI: sarifToolURI,
+			Rules:          descriptors,
+		}},
+		// Rule errors are problems of the rules rather than of the model,
+		// so they are reported as notifications instead of results.
+		Invocations: []sarifInvocation{{
+			ExecutionSuccessful:        len(notifications) == 0,
+			ToolExecutionNotifications: notifications,
+		}},
+		Results: results,
+	}
+	if baseURI := modelsourceBaseURI(modelSourcePath); baseURI != "" {
+		run.OriginalURIBaseIDs = map[string]sarifArtifactLocation{
+			sarifModelsourceURI: {URI: baseURI},
+		}
+	}
+
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}
+}
+
+// sarifTestcaseResults reports each violation of a failed testcase as a
+// result, so code scanning can annotate its line, or the testcase itself when
+// it has no violations. Warnings of rules below lint.failOn are reported at
+// most at the warning level.
+func sarifTestcaseResults(descriptor sarifReportingDescriptor, ruleIndex int, tc Testcase, message string, warning bool) []sarifResult {
+	violations := tc.Violations
+	if len(violations) == 0 {
+		violations = []Violation{{Message: message}}
+	}
+	results := make([]sarifResult, 0, len(violations))
+	for _, v := range violations {
+		level := descriptor.DefaultConfiguration.Level
+		if v.Severity != "" {
+			level = sarifLevel(v.Severity)
+		}
+		if warning && level == "error" {
+			level = "warning"
+		}
+		results = append(results, sarifResult{
+			RuleID:    descriptor.ID,
+			RuleIndex: ruleIndex,
+			Level:     level,
+			Message:   sarifMessage{Text: v.Message},
+			Locations: []sarifLocation{sarifTestcaseLocation(tc, v)},
+		})
+	}
+	return results
+}
+
+// sarifTestcaseLocation points at the testcase document, or the document a
+// violation of a project-scoped rule names, and, when known, the line and
+// element of the violation.
+func sarifTestcaseLocation(tc Testcase, v Violation) sarifLocation {
+	document := tc.Name
+	if v.Document != "" {
+		document = strings.TrimPrefix(filepath.ToSlash(v.Document), "/")
+	}
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{
+				URI:       sarifRelativeURI(document),
+				URIBaseID: sarifModelsourceURI,
+			},
+		},
+	}
+	if v.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: v.Line, StartColumn: v.Column}
+	}
+	if tc.OriginalPath != "" {
+		location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{
+			FullyQualifiedName: tc.OriginalPath,
+			Kind:               "resource",
+		})
+	}
+	if v.Element != "" {
+		location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{
+			FullyQualifiedName: v.Element,
+			Kind:               "object",
+		})
+	}
+	return location
+}
+
+// modelsourceBaseURI returns the modelsource directory as a file URI with a
+// trailing slash, as SARIF requires for uriBaseId targets.
+func modelsourceBaseURI(modelSourcePath string) string {
+	if strings.TrimSpace(modelSourcePath) == "" {
+		return ""
+	}
+	absPath, err := filepath.Abs(modelSourcePath)
+	if err != nil {
+		return ""
+	}
+	uri := filepath.ToSlash(absPath)
+	if !strings.HasPrefix(uri, "/") {
+		uri = "/" + uri
+	}
+	if !strings.HasSuffix(uri, "/") {
+		uri += "/"
+	}
+	return (&url.URL{Scheme: "file", Path: uri}).String()
+}
+
+// sarifRelativeURI percent-encodes each segment of a document path, since
+// Mendix module and document names may contain spaces, "#" or "%".
+func sarifRelativeURI(documentPath string) string {
+	segments := strings.Split(filepath.ToSlash(documentPath), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func writeSarifReport(sarifFile string, testsuites []Testsuite, rules []Rule, modelSourcePath string) error {
+	file, err := os.Create(sarifFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(buildSarifLog(testsuites, rules, modelSourcePath))
+}
@@ -0,0 +1,206 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSarifLevel(t *testing.T) {
+	tests := map[string]string{
+		"HIGH":   "error",
+		"high":   "error",
+		"MEDIUM": "warning",
+		"LOW":    "note",
+		"":       "warning",
+	}
+	for severity, expected := range tests {
+		if got := sarifLevel(severity); got != expected {
+			t.Errorf("sarifLevel(%q) = %q, expected %q", severity, got, expected)
+		}
+	}
+}
+
+func TestBuildSarifLog(t *testing.T) {
+	rules := []Rule{
+		{
+			Title:       "Ensure security rules are active",
+			Description: "Any serious app needs entity access security configured",
+			Category:    "Security",
+			Severity:    "HIGH",
+			RuleNumber:  "001_0003",
+			Remediation: "Set Security check to production in Project Security",
+			RuleName:    "SecurityChecks",
+			Path:        "rules/001_0003_security_checks.rego",
+			Language:    LanguageRego,
+		},
+		{
+			Title:      "Passing rule",
+			Severity:   "LOW",
+			RuleNumber: "001_0004",
+			Path:       "rules/001_0004_passing.js",
+			Language:   LanguageJavascript,
+		},
+	}
+	testsuites := []Testsuite{
+		{
+			Name: "rules/001_0003_security_checks.rego",
+			Testcases: []Testcase{
+				{
+					Name:         "Security$ProjectSecurity.yaml",
+					OriginalPath: "Security$ProjectSecurity.yaml",
+					Failure:      &Failure{Message: "Security check is not enabled", Type: "AssertionError"},
+				},
+				{Name: "Other.yaml"},
+			},
+		},
+		{
+			Name:      "rules/001_0004_passing.js",
+			Testcases: []Testcase{{Name: "Other.yaml"}},
+		},
+	}
+
+	sarif := buildSarifLog(testsuites, rules, t.TempDir())
+
+	if sarif.Version != "2.1.0" {
+		t.Fatalf("expected SARIF version 2.1.0, got %s", sarif.Version)
+	}
+	if len(sarif.Runs) != 1 {
+		t.Fatalf("expected one run, got %d", len(sarif.Runs))
+	}
+	run := sarif.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 {
+		t.Fatalf("expected two reporting descriptors, got %d", len(run.Tool.Driver.Rules))
+	}
+
+	descriptor := run.Tool.Driver.Rules[0]
+	if descriptor.ID != "001_0003" || descriptor.Name != "SecurityChecks" {
+		t.Fatalf("unexpected descriptor identity: %+v", descriptor)
+	}
+	if descriptor.ShortDescription == nil || descriptor.ShortDescription.Text != rules[0].Title {
+		t.Fatalf("expected title as short description, got %+v", descriptor.ShortDescription)
+	}
+	if descriptor.Help == nil || descriptor.Help.Text != rules[0].Remediation {
+		t.Fatalf("expected remediation as help, got %+v", descriptor.Help)
+	}
+	if descriptor.Properties.Severity != "HIGH" || descriptor.Properties.RuleNumber != "001_0003" {
+		t.Fatalf("unexpected descriptor properties: %+v", descriptor.Properties)
+	}
+	if run.Tool.Driver.Rules[1].FullDescription != nil {
+		t.Fatal("expected empty description to be omitted")
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("expected one result, got %d", len(run.Results))
+	}
+	result := run.Results[0]
+	if result.RuleID != "001_0003" || result.RuleIndex != 0 || result.Level != "error" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	location := result.Locations[0]
+	if location.PhysicalLocation.ArtifactLocation.URI != "Security$ProjectSecurity.yaml" {
+		t.Fatalf("unexpected artifact uri: %s", location.PhysicalLocation.ArtifactLocation.URI)
+	}
+	if location.PhysicalLocation.ArtifactLocation.URIBaseID != sarifModelsourceURI {
+		t.Fatalf("expected uriBaseId %s, got %s", sarifModelsourceURI, location.PhysicalLocation.ArtifactLocation.URIBaseID)
+	}
+	if len(location.LogicalLocations) != 1 || location.LogicalLocations[0].FullyQualifiedName != "Security$ProjectSecurity.yaml" {
+		t.Fatalf("expected original path as logical location, got %+v", location.LogicalLocations)
+	}
+	base, ok := run.OriginalURIBaseIDs[sarifModelsourceURI]
+	if !ok || !strings.HasPrefix(base.URI, "file://") || !strings.HasSuffix(base.URI, "/") {
+		t.Fatalf("expected modelsource base uri, got %+v", run.OriginalURIBaseIDs)
+	}
+}
+
+func TestBuildSarifLog_EscapesURIs(t *testing.T) {
+	rules := []Rule{{Severity: "HIGH", RuleNumber: "001_0006", Path: "rules/001_0006_project.js", Language: LanguageJavascript}}
+	testsuites := []Testsuite{
+		{
+			Name: "rules/001_0006_project.js",
+			Testcases: []Testcase{
+				{
+					Name:    "Administration/User Management/Doc #1 100%.yaml",
+					Failure: &Failure{Message: "failed", Type: "AssertionError"},
+				},
+				{
+					Name:       "Metadata.yaml",
+					Failure:    &Failure{Message: "failed", Type: "AssertionError"},
+					Violations: []Violation{{Message: "failed", Document: "My Module/Pages/Home.Forms$Page.yaml"}},
+				},
+			},
+		},
+	}
+	modelSourcePath := filepath.Join(t.TempDir(), "model source")
+
+	run := buildSarifLog(testsuites, rules, modelSourcePath).Runs[0]
+	uris := []string{}
+	for _, result := range run.Results {
+		uris = append(uris, result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	expected := []string{"Administration/User%20Management/Doc%20%231%20100%25.yaml", "My%20Module/Pages/Home.Forms$Page.yaml"}
+	if strings.Join(uris, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected artifact uris %v, got %v", expected, uris)
+	}
+	if base := run.OriginalURIBaseIDs[sarifModelsourceURI].URI; !strings.HasPrefix(base, "file:///") || !strings.HasSuffix(base, "/model%20source/") {
+		t.Fatalf("expected an escaped modelsource base uri, got %s", base)
+	}
+}
+
+func TestBuildSarifLog_WarningsAndRuleErrors(t *testing.T) {
+	rules := []Rule{{Severity: "HIGH", RuleNumber: "001_0005", Path: "rules/001_0005_warning.js", Language: LanguageJavascript}}
+	testsuites := []Testsuite{
+		{
+			Name: "rules/001_0005_warning.js",
+			Testcases: []Testcase{
+				{
+					Name:       "Warned.yaml",
+					Warning:    &Warning{Message: "Entity has no documentation", Type: "AssertionError"},
+					Violations: []Violation{{Message: "Entity has no documentation", Line: 3}},
+				},
+				{Name: "Broken.yaml", Error: &RuleError{Message: "rule threw", Type: ruleErrorRuntime}},
+			},
+		},
+	}
+
+	run := buildSarifLog(testsuites, rules, "").Runs[0]
+	if len(run.Results) != 1 {
+		t.Fatalf("expected the warning as one result, got %+v", run.Results)
+	}
+	if result := run.Results[0]; result.Level != "warning" || result.Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Fatalf("unexpected warning result: %+v", result)
+	}
+	if len(run.Invocations) != 1 || run.Invocations[0].ExecutionSuccessful {
+		t.Fatalf("expected an unsuccessful invocation, got %+v", run.Invocations)
+	}
+	notifications := run.Invocations[0].ToolExecutionNotifications
+	if len(notifications) != 1 || notifications[0].Level != "error" || notifications[0].Message.Text != "rule threw" {
+		t.Fatalf("expected the rule error as a notification, got %+v", notifications)
+	}
+	if rule := notifications[0].AssociatedRule; rule == nil || rule.ID != "001_0005" || rule.Index != 0 {
+		t.Fatalf("expected the notification to reference the rule, got %+v", rule)
+	}
+}
+
+func TestEvalAll_WithSarifReport(t *testing.T) {
+	sarifPath := filepath.Join(t.TempDir(), "report.sarif")
+
+	err := EvalAll("./../resources/rules", "./../resources/modelsource-v1", "", "", sarifPath, false, false, nil)
+	if err != nil {
+		t.Fatalf("Expected no failures: %v", err)
+	}
+
+	content, err := os.ReadFile(sarifPath)
+	if err != nil {
+		t.Fatalf("Expected SARIF report to be created: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(content, &decoded); err != nil {
+		t.Fatalf("Expected SARIF report to be valid JSON: %v", err)
+	}
+	if decoded["version"] != "2.1.0" {
+		t.Fatalf("expected SARIF version 2.1.0, got %v", decoded["version"])
+	}
+}
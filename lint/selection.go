@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigRuleSelector matches rules for rules.include and rules.exclude. All
+// set fields must match. Rule is a glob on the rule number, e.g. "001_*".
+// A plain string in YAML is shorthand for a selector with only Rule set.
+type ConfigRuleSelector struct {
+	Rule     string `yaml:"rule,omitempty"`
+	Category string `yaml:"category,omitempty"`
+	Severity string `yaml:"severity,omitempty"`
+	Language string `yaml:"language,omitempty"`
+}
+
+func (s *ConfigRuleSelector) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*s = ConfigRuleSelector{Rule: value.Value}
+		return nil
+	}
+	type configRuleSelectorAlias ConfigRuleSelector
+	var decoded configRuleSelectorAlias
+	if err := value.Decode(&decoded); err != nil {
+		return err
+	}
+	*s = ConfigRuleSelector(decoded)
+	return nil
+}
+
+func (s ConfigRuleSelector) String() string {
+	parts := make([]string, 0, 4)
+	if s.Rule != "" {
+		parts = append(parts, "rule="+s.Rule)
+	}
+	if s.Category != "" {
+		parts = append(parts, "category="+s.Category)
+	}
+	if s.Severity != "" {
+		parts = append(parts, "severity="+s.Severity)
+	}
+	if s.Language != "" {
+		parts = append(parts, "language="+s.Language)
+	}
+	return strings.Join(parts, ",")
+}
+
+// matches reports whether rule satisfies every field set on the selector. An
+// empty selector matches nothing so that a stray "{}" cannot select all rules.
+func (s ConfigRuleSelector) matches(rule Rule) bool {
+	if s.Rule == "" && s.Category == "" && s.Severity == "" && s.Language == "" {
+		return false
+	}
+	if s.Rule != "" {
+		matched, err := path.Match(strings.TrimSpace(s.Rule), rule.RuleNumber)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if s.Category != "" && !strings.EqualFold(strings.TrimSpace(s.Category), rule.Category) {
+		return false
+	}
+	if s.Severity != "" && normalizeSeverity(s.Severity) != normalizeSeverity(rule.Severity) {
+		return false
+	}
+	if s.Language != "" && !strings.EqualFold(strings.TrimSpace(s.Language), rule.Language) {
+		return false
+	}
+	return true
+}
+
+func validateRuleSelectors(key string, selectors []ConfigRuleSelector) error {
+	for _, selector := range selectors {
+		if selector.Rule != "" {
+			if _, err := path.Match(strings.TrimSpace(selector.Rule), ""); err != nil {
+				return fmt.Errorf("invalid %s rule pattern %q: %w", key, selector.Rule, err)
+			}
+		}
+		if selector.Severity != "" {
+			if err := validateSeverity(selector.Severity); err != nil {
+				return fmt.Errorf("invalid %s severity: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func matchesAnySelector(selectors []ConfigRuleSelector, rule Rule) bool {
+	for _, selector := range selectors {
+		if selector.matches(rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandLineRuleSelectors turns the lint --rule and --category flags into
+// rules.include selectors. Rules and categories are combined so that a rule
+// must match one of the rule globs and one of the categories.
+func CommandLineRuleSelectors(rules []string, categories []string) []ConfigRuleSelector {
+	if len(rules) == 0 && len(categories) == 0 {
+		return nil
+	}
+	if len(rules) == 0 {
+		rules = []string{""}
+	}
+	if len(categories) == 0 {
+		categories = []string{""}
+	}
+	selectors := make([]ConfigRuleSelector, 0, len(rules)*len(categories))
+	for _, rule := range rules {
+		for _, category := range categories {
+			selectors = append(selectors, ConfigRuleSelector{Rule: rule, Category: category})
+		}
+	}
+	return selectors
+}
+
+// selectRules applies rules.include, rules.exclude and rules.minSeverity so
+// that unselected rules are never evaluated. Rules with an unknown severity
+// are kept by rules.minSeverity, matching how lint.failOn treats them.
+func selectRules(rules []Rule) ([]Rule, error) {
+	cfg := getConfig()
+	if cfg == nil {
+		return rules, nil
+	}
+	include := cfg.Rules.Include
+	exclude := cfg.Rules.Exclude
+	minSeverity := strings.TrimSpace(cfg.Rules.MinSeverity)
+	if len(include) == 0 && len(exclude) == 0 && minSeverity == "" {
+		return rules, nil
+	}
+
+	if err := validateRuleSelectors("rules.include", include); err != nil {
+		return nil, err
+	}
+	if err := validateRuleSelectors("rules.exclude", exclude); err != nil {
+		return nil, err
+	}
+	if minSeverity != "" {
+		if err := validateSeverity(minSeverity); err != nil {
+			return nil, fmt.Errorf("invalid rules.minSeverity: %w", err)
+		}
+	}
+
+	selected := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		if len(include) > 0 && !matchesAnySelector(include, rule) {
+			log.Debugf("Rule %s not selected by rules.include", rule.Path)
+			continue
+		}
+		if matchesAnySelector(exclude, rule) {
+			log.Debugf("Rule %s excluded by rules.exclude", rule.Path)
+			continue
+		}
+		if minSeverity != "" && !failsRun(rule.Severity, minSeverity) {
+			log.Debugf("Rule %s below rules.minSeverity %s", rule.Path, minSeverity)
+			continue
+		}
+		selected = append(selected, rule)
+	}
+	log.Infof("Selected %d of %d rules", len(selected), len(rules))
+	return selected, nil
+}
@@ -0,0 +1,142 @@
+package lint
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func selectionTestRules() []Rule {
+	return []Rule{
+		{Path: "a.rego", RuleNumber: "001_0001", Category: "Security", Severity: "HIGH", Language: LanguageRego},
+		{Path: "b.js", RuleNumber: "001_0002", Category: "Security", Severity: "LOW", Language: LanguageJavascript},
+		{Path: "c.rego", RuleNumber: "002_0001", Category: "Maintainability", Severity: "MEDIUM", Language: LanguageRego},
+		{Path: "d.ts", RuleNumber: "003_0001", Category: "Performance", Language: LanguageTypescript},
+	}
+}
+
+func selectedRuleNumbers(rules []Rule) []string {
+	numbers := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		numbers = append(numbers, rule.RuleNumber)
+	}
+	return numbers
+}
+
+func TestSelectRules(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+
+	tests := []struct {
+		name     string
+		rules    ConfigRulesSpec
+		expected []string
+	}{
+		{
+			name:     "no selection keeps all rules",
+			expected: []string{"001_0001", "001_0002", "002_0001", "003_0001"},
+		},
+		{
+			name:     "include by rule number glob",
+			rules:    ConfigRulesSpec{Include: []ConfigRuleSelector{{Rule: "001_*"}}},
+			expected: []string{"001_0001", "001_0002"},
+		},
+		{
+			name:     "include fields must all match",
+			rules:    ConfigRulesSpec{Include: []ConfigRuleSelector{{Category: "security", Severity: "high"}}},
+			expected: []string{"001_0001"},
+		},
+		{
+			name: "exclude wins over include",
+			rules: ConfigRulesSpec{
+				Include: []ConfigRuleSelector{{Language: "rego"}},
+				Exclude: []ConfigRuleSelector{{Rule: "002_*"}},
+			},
+			expected: []string{"001_0001"},
+		},
+		{
+			name:     "min severity keeps unknown severities",
+			rules:    ConfigRulesSpec{MinSeverity: "MEDIUM"},
+			expected: []string{"001_0001", "002_0001", "003_0001"},
+		},
+		{
+			name:     "command line selectors combine rule and category",
+			rules:    ConfigRulesSpec{Include: CommandLineRuleSelectors([]string{"001_*", "002_*"}, []string{"Security"})},
+			expected: []string{"001_0001", "001_0002"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetConfig(&Config{Rules: tt.rules})
+			selected, err := selectRules(selectionTestRules())
+			if err != nil {
+				t.Fatalf("selectRules returned error: %v", err)
+			}
+			got := selectedRuleNumbers(selected)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectRules_InvalidSelectors(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+
+	for _, rules := range []ConfigRulesSpec{
+		{Include: []ConfigRuleSelector{{Rule: "[001"}}},
+		{Exclude: []ConfigRuleSelector{{Severity: "CRITICAL"}}},
+		{MinSeverity: "URGENT"},
+	} {
+		SetConfig(&Config{Rules: rules})
+		if _, err := selectRules(selectionTestRules()); err == nil {
+			t.Fatalf("expected error for %+v", rules)
+		}
+	}
+}
+
+func TestConfigRuleSelector_UnmarshalYAML(t *testing.T) {
+	var spec ConfigRulesSpec
+	content := `include:
+  - "001_*"
+  - category: Security
+    severity: HIGH
+exclude:
+  - rule: 001_0002
+`
+	if err := yaml.Unmarshal([]byte(content), &spec); err != nil {
+		t.Fatalf("Failed to parse selectors: %v", err)
+	}
+	if len(spec.Include) != 2 || spec.Include[0].Rule != "001_*" || spec.Include[1].Category != "Security" {
+		t.Fatalf("unexpected include selectors: %+v", spec.Include)
+	}
+	if len(spec.Exclude) != 1 || spec.Exclude[0].Rule != "001_0002" {
+		t.Fatalf("expected unquoted rule number to stay a string, got %+v", spec.Exclude)
+	}
+}
+
+func TestEvalAll_SkipsUnselectedRules(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	writeAlwaysFailRule(t, rulesDir, "099_0001", `["always fails"]`)
+	if err := writeTestFile(modelDir, "Doc.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	SetConfig(&Config{Rules: ConfigRulesSpec{Exclude: []ConfigRuleSelector{{Rule: "099_*"}}}})
+	if err := EvalAll(rulesDir, modelDir, "", "", "", false, false, nil); err != nil {
+		t.Fatalf("expected excluded rule not to be evaluated, got: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"encoding/json"
+
+	"github.com/open-policy-agent/opa/storage"
+	"github.com/open-policy-agent/opa/storage/inmem"
+)
+
+// mergeRuleSettings returns base overlaid with overlay. Keys are merged one
+// level deep: a setting in overlay replaces the whole value in base.
+func mergeRuleSettings(base map[string]interface{}, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		merged[key] = value
+	}
+	return merged
+}
+
+// ruleSettings returns the settings passed to a rule: the defaults declared in
+// its custom.settings metadata overlaid with rules.settings.<rulenumber> from
+// mxlint.yaml. Values are normalized to JSON types so Rego and JavaScript see
+// the same numbers, lists and objects.
+func ruleSettings(rule Rule) map[string]interface{} {
+	var configured map[string]interface{}
+	if cfg := getConfig(); cfg != nil && rule.RuleNumber != "" {
+		configured = cfg.Rules.Settings[rule.RuleNumber]
+	}
+	return normalizeSettings(mergeRuleSettings(rule.Settings, configured))
+}
+
+func normalizeSettings(settings map[string]interface{}) map[string]interface{} {
+	normalized := map[string]interface{}{}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		log.Warnf("Ignoring rule settings that cannot be represented as JSON: %v", err)
+		return normalized
+	}
+	if err := json.Unmarshal(data, &normalized); err != nil || normalized == nil {
+		return map[string]interface{}{}
+	}
+	return normalized
+}
+
+// regoDataStore exposes settings to Rego rules as data.mxlint.settings and the
+// external data files as data.mxlint.external.
+func regoDataStore(settings map[string]interface{}) storage.Store {
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	return inmem.NewFromObject(map[string]interface{}{
+		"mxlint": map[string]interface{}{
+			"settings": settings,
+			"external": getRulesData(),
+		},
+	})
+}
+
+// testCaseSettings returns the rule defaults overlaid with the optional
+// settings of a rule test case. Project configuration is not applied so that
+// rule tests behave the same in every project.
+func testCaseSettings(rule Rule, testCase interface{}) map[string]interface{} {
+	var overlay map[string]interface{}
+	switch tcMap := testCase.(type) {
+	case map[interface{}]interface{}:
+		if settings, ok := tcMap["settings"].(map[interface{}]interface{}); ok {
+			overlay = convertToStringKeyMap(settings)
+		}
+	case map[string]interface{}:
+		overlay, _ = tcMap["settings"].(map[string]interface{})
+	}
+	return normalizeSettings(mergeRuleSettings(rule.Settings, overlay))
+}
@@ -0,0 +1,174 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const settingsRegoRule = `# METADATA
+# title: Limit attributes
+# description: Entities should not have too many attributes
+# custom:
+#  rulenumber: 099_0030
+#  input: .*\.yaml
+#  settings:
+#    maxAttributes: 2
+package test.settings_limit
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains error if {
+    count(input.Attributes) > data.mxlint.settings.maxAttributes
+    error := sprintf("more than %v attributes", [data.mxlint.settings.maxAttributes])
+}
+`
+
+const settingsJavascriptRule = `
+const metadata = {
+    title: "Allowed prefixes",
+    description: "Names must use an allowed prefix",
+    custom: {
+        rulenumber: "099_0031",
+        input: ".*\\.yaml",
+        settings: { prefixes: ["ACT_"] }
+    }
+};
+
+function rule(input, settings) {
+    const ok = settings.prefixes.some((prefix) => input.Name.startsWith(prefix));
+    return { allow: ok, errors: ok ? [] : ["name must start with one of " + settings.prefixes.join(", ")] };
+}
+`
+
+func TestLoadMergedConfig_RuleSettings(t *testing.T) {
+	projectDir := t.TempDir()
+	setDefaultConfigForTest(t, `rules:
+  settings:
+    099_0030:
+      maxAttributes: 10
+      other: default
+`)
+	projectConfig := `rules:
+  settings:
+    099_0030:
+      maxAttributes: 3
+`
+	if err := os.WriteFile(filepath.Join(projectDir, "mxlint.yaml"), []byte(projectConfig), 0644); err != nil {
+		t.Fatalf("failed to write project config: %v", err)
+	}
+
+	cfg, err := LoadMergedConfig(projectDir)
+	if err != nil {
+		t.Fatalf("LoadMergedConfig returned error: %v", err)
+	}
+	settings := cfg.Rules.Settings["099_0030"]
+	if settings["maxAttributes"] != 3 || settings["other"] != "default" {
+		t.Fatalf("expected project settings merged over defaults, got %v", settings)
+	}
+}
+
+func TestRuleSettings_Rego(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	rulePath := filepath.Join(rulesDir, "099_0030_limit.rego")
+	if err := os.WriteFile(rulePath, []byte(settingsRegoRule), 0644); err != nil {
+		t.Fatalf("Failed to write rego file: %v", err)
+	}
+	if err := writeTestFile(modelDir, "Entity.yaml", "Attributes: [a, b, c]\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	rule, err := parseRuleMetadata_Rego(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+
+	SetConfig(&Config{})
+	testsuite, err := evalTestsuite(*rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testsuite.Failures != 1 || testsuite.Testcases[0].Failure.Message != "more than 2 attributes" {
+		t.Fatalf("expected metadata default to apply, got %+v", testsuite.Testcases)
+	}
+
+	SetConfig(&Config{Rules: ConfigRulesSpec{Settings: map[string]map[string]interface{}{
+		"099_0030": {"maxAttributes": 3},
+	}}})
+	testsuite, err = evalTestsuite(*rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testsuite.Failures != 0 {
+		t.Fatalf("expected configured setting to override default, got %+v", testsuite.Testcases)
+	}
+}
+
+func TestRuleSettings_Javascript(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	rulePath := filepath.Join(rulesDir, "099_0031_prefixes.js")
+	if err := os.WriteFile(rulePath, []byte(settingsJavascriptRule), 0644); err != nil {
+		t.Fatalf("Failed to write js file: %v", err)
+	}
+	if err := writeTestFile(modelDir, "Microflow.yaml", "Name: SUB_Process\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	rule, err := parseRuleMetadata_Javascript(rulePath)
+	if err != nil {
+		t.Fatalf("Failed to parse rule: %v", err)
+	}
+
+	SetConfig(&Config{})
+	testsuite, err := evalTestsuite(*rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testsuite.Failures != 1 {
+		t.Fatalf("expected default prefixes to reject SUB_, got %+v", testsuite.Testcases)
+	}
+
+	SetConfig(&Config{Rules: ConfigRulesSpec{Settings: map[string]map[string]interface{}{
+		"099_0031": {"prefixes": []interface{}{"ACT_", "SUB_"}},
+	}}})
+	testsuite, err = evalTestsuite(*rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to evaluate rule: %v", err)
+	}
+	if testsuite.Failures != 0 {
+		t.Fatalf("expected configured prefixes to accept SUB_, got %+v", testsuite.Testcases)
+	}
+}
+
+func TestCacheConfigHashIncludesRuleSettings(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+
+	SetConfig(&Config{})
+	empty := computeCacheConfigHash()
+
+	SetConfig(&Config{Rules: ConfigRulesSpec{Settings: map[string]map[string]interface{}{
+		"099_0030": {"maxAttributes": 3},
+	}}})
+	first := computeCacheConfigHash()
+
+	SetConfig(&Config{Rules: ConfigRulesSpec{Settings: map[string]map[string]interface{}{
+		"099_0030": {"maxAttributes": 4},
+	}}})
+	second := computeCacheConfigHash()
+
+	if first == empty || first == second {
+		t.Fatalf("expected rule settings to change the cache config hash, got %s, %s, %s", empty, first, second)
+	}
+}
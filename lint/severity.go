@@ -0,0 +1,142 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	SeverityLow    = "LOW"
+	SeverityMedium = "MEDIUM"
+	SeverityHigh   = "HIGH"
+)
+
+// severityOrder lists the known rule severities from least to most severe.
+var severityOrder = []string{SeverityLow, SeverityMedium, SeverityHigh}
+
+func normalizeSeverity(severity string) string {
+	return strings.ToUpper(strings.TrimSpace(severity))
+}
+
+// severityRank returns the position of severity in severityOrder, or -1 when
+// the severity is unknown.
+func severityRank(severity string) int {
+	normalized := normalizeSeverity(severity)
+	for i, known := range severityOrder {
+		if known == normalized {
+			return i
+		}
+	}
+	return -1
+}
+
+func validateSeverity(severity string) error {
+	if severityRank(severity) < 0 {
+		return fmt.Errorf("unknown severity %q (expected one of %s)", severity, strings.Join(severityOrder, ", "))
+	}
+	return nil
+}
+
+// failsRun reports whether failures of a rule with the given severity fail
+// the run under the failOn threshold. Rules with an unknown severity always
+// fail so that a typo in rule metadata cannot silently downgrade findings.
+func failsRun(severity string, failOn string) bool {
+	if strings.TrimSpace(failOn) == "" {
+		return true
+	}
+	rank := severityRank(severity)
+	if rank < 0 {
+		return true
+	}
+	return rank >= severityRank(failOn)
+}
+
+// testcaseFailsRun reports whether a failed testcase fails the run under
+// the failOn threshold. Each violation counts with its own severity, or the
+// rule severity when it sets none, so the most severe violation decides.
+func testcaseFailsRun(tc Testcase, ruleSeverity string, failOn string) bool {
+	if len(tc.Violations) == 0 {
+		return failsRun(ruleSeverity, failOn)
+	}
+	for _, v := range tc.Violations {
+		severity := v.Severity
+		if severity == "" {
+			severity = ruleSeverity
+		}
+		if failsRun(severity, failOn) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyFailOnThreshold turns failures below the failOn threshold into
+// warnings, which are reported but do not fail the run.
+func applyFailOnThreshold(testsuite *Testsuite, rule Rule, failOn string) {
+	if testsuite == nil {
+		return
+	}
+	for i := range testsuite.Testcases {
+		tc := &testsuite.Testcases[i]
+		if tc.Failure == nil || testcaseFailsRun(*tc, rule.Severity, failOn) {
+			continue
+		}
+		tc.Warning = &Warning{
+			Message: tc.Failure.Message,
+			Type:    tc.Failure.Type,
+		}
+		tc.Failure = nil
+		testsuite.Failures--
+		testsuite.Warnings++
+	}
+}
+
+// severityLabel returns the rule severity used in summaries.
+func severityLabel(severity string) string {
+	if normalized := normalizeSeverity(severity); normalized != "" {
+		return normalized
+	}
+	return "UNKNOWN"
+}
+
+// logSeveritySummary logs failure and warning counts per rule severity.
+func logSeveritySummary(testsuites []Testsuite, rules []Rule) {
+	failures := map[string]int{}
+	warnings := map[string]int{}
+	for i, ts := range testsuites {
+		label := severityLabel(rules[i].Severity)
+		failures[label] += ts.Failures
+		warnings[label] += ts.Warnings
+	}
+
+	if line := formatSeverityCounts(failures); line != "" {
+		log.Infof("Failures by severity: %s", line)
+	}
+	if line := formatSeverityCounts(warnings); line != "" {
+		log.Infof("Warnings by severity: %s", line)
+	}
+}
+
+func formatSeverityCounts(counts map[string]int) string {
+	labels := make([]string, 0, len(severityOrder)+1)
+	for i := len(severityOrder) - 1; i >= 0; i-- {
+		labels = append(labels, severityOrder[i])
+	}
+	unknown := make([]string, 0)
+	for label := range counts {
+		if severityRank(label) < 0 {
+			unknown = append(unknown, label)
+		}
+	}
+	slices.Sort(unknown)
+	labels = append(labels, unknown...)
+
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if counts[label] > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", label, counts[label]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
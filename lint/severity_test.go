@@ -0,0 +1,117 @@
+package lint
+
+import (
+	"testing"
+)
+
+func TestFailsRun(t *testing.T) {
+	tests := []struct {
+		severity string
+		failOn   string
+		expected bool
+	}{
+		{severity: "LOW", failOn: "", expected: true},
+		{severity: "LOW", failOn: "HIGH", expected: false},
+		{severity: "MEDIUM", failOn: "HIGH", expected: false},
+		{severity: "HIGH", failOn: "HIGH", expected: true},
+		{severity: "medium", failOn: "MEDIUM", expected: true},
+		{severity: "LOW", failOn: "MEDIUM", expected: false},
+		{severity: "", failOn: "HIGH", expected: true},
+		{severity: "CRITICAL", failOn: "HIGH", expected: true},
+	}
+	for _, tt := range tests {
+		if got := failsRun(tt.severity, tt.failOn); got != tt.expected {
+			t.Errorf("failsRun(%q, %q) = %v, expected %v", tt.severity, tt.failOn, got, tt.expected)
+		}
+	}
+}
+
+func TestApplyFailOnThreshold(t *testing.T) {
+	newSuite := func() *Testsuite {
+		return &Testsuite{
+			Failures: 1,
+			Testcases: []Testcase{
+				{Name: "A.yaml", Failure: &Failure{Message: "bad", Type: "AssertionError"}},
+				{Name: "B.yaml"},
+			},
+		}
+	}
+
+	t.Run("below threshold becomes warning", func(t *testing.T) {
+		suite := newSuite()
+		applyFailOnThreshold(suite, Rule{Severity: "LOW"}, "MEDIUM")
+		if suite.Failures != 0 || suite.Warnings != 1 {
+			t.Fatalf("expected 0 failures and 1 warning, got %d and %d", suite.Failures, suite.Warnings)
+		}
+		tc := suite.Testcases[0]
+		if tc.Failure != nil || tc.Warning == nil || tc.Warning.Message != "bad" {
+			t.Fatalf("expected failure to be converted to warning, got %+v", tc)
+		}
+	})
+
+	t.Run("at threshold keeps failure", func(t *testing.T) {
+		suite := newSuite()
+		applyFailOnThreshold(suite, Rule{Severity: "MEDIUM"}, "MEDIUM")
+		if suite.Failures != 1 || suite.Warnings != 0 || suite.Testcases[0].Warning != nil {
+			t.Fatalf("expected failure to remain, got %+v", suite)
+		}
+	})
+
+	t.Run("violation severity decides", func(t *testing.T) {
+		suite := newSuite()
+		suite.Testcases[0].Violations = []Violation{{Message: "bad", Severity: "LOW"}, {Message: "worse", Severity: "HIGH"}}
+		applyFailOnThreshold(suite, Rule{Severity: "MEDIUM"}, "HIGH")
+		if suite.Failures != 1 || suite.Testcases[0].Warning != nil {
+			t.Fatalf("expected a HIGH violation to fail failOn HIGH, got %+v", suite)
+		}
+
+		suite = newSuite()
+		suite.Testcases[0].Violations = []Violation{{Message: "bad", Severity: "LOW"}}
+		applyFailOnThreshold(suite, Rule{Severity: "HIGH"}, "MEDIUM")
+		if suite.Failures != 0 || suite.Warnings != 1 {
+			t.Fatalf("expected a LOW violation of a HIGH rule to be a warning, got %+v", suite)
+		}
+	})
+}
+
+func TestFormatSeverityCounts(t *testing.T) {
+	got := formatSeverityCounts(map[string]int{"LOW": 2, "HIGH": 1, "UNKNOWN": 3, "MEDIUM": 0})
+	expected := "HIGH=1, LOW=2, UNKNOWN=3"
+	if got != expected {
+		t.Fatalf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestEvalAll_FailOnThreshold(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	writeAlwaysFailRule(t, rulesDir, "099_0001", `["always fails"]`)
+	if err := writeTestFile(modelDir, "Doc.yaml", `Name: "Test"`); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	SetConfig(&Config{Lint: ConfigLintSpec{FailOn: "HIGH"}})
+	if err := EvalAll(rulesDir, modelDir, "", "", "", false, false, nil); err == nil {
+		t.Fatal("expected HIGH rule to fail with failOn HIGH")
+	}
+
+	// writeAlwaysFailRule declares severity HIGH; lower it to LOW.
+	writeAlwaysFailRuleWithSeverity(t, rulesDir, "099_0001", "LOW")
+	result, err := EvalAllWithResults(rulesDir, modelDir, "", "", "", false, false, nil)
+	if err != nil {
+		t.Fatalf("expected LOW rule to only warn with failOn HIGH, got: %v", err)
+	}
+	suite := result.(TestSuites).Testsuites[0]
+	if suite.Warnings != 1 || suite.Failures != 0 {
+		t.Fatalf("expected one warning and no failures, got %+v", suite)
+	}
+
+	SetConfig(&Config{Lint: ConfigLintSpec{FailOn: "SEVERE"}})
+	if err := EvalAll(rulesDir, modelDir, "", "", "", false, false, nil); err == nil {
+		t.Fatal("expected invalid failOn to be rejected")
+	}
+}
@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// lint.skip keys other than document paths select documents by module, by
+// document type or by regular expression.
+const (
+	skipSelectorModule = "module:"
+	skipSelectorType   = "type:"
+	skipSelectorRegexp = "re:"
+)
+
+// Precedence of lint.skip keys when several match a document. The first
+// matching key with an entry for the rule provides the skip reason.
+const (
+	skipPrecedencePath = iota
+	skipPrecedenceGlob
+	skipPrecedenceRegexp
+	skipPrecedenceType
+	skipPrecedenceModule
+	skipPrecedenceAll
+)
+
+// skipPatterns caches the regular expressions compiled from glob and re:
+// keys, since every (document, rule) pair is checked against lint.skip.
+var skipPatterns sync.Map
+
+func isSkipSelector(skipPath string) bool {
+	return strings.HasPrefix(skipPath, skipSelectorModule) ||
+		strings.HasPrefix(skipPath, skipSelectorType) ||
+		strings.HasPrefix(skipPath, skipSelectorRegexp)
+}
+
+func isSkipGlob(skipPath string) bool {
+	return skipPath != skipPathAllDocuments && !isSkipSelector(skipPath) && strings.ContainsAny(skipPath, "*?[")
+}
+
+func skipPathPrecedence(skipPath string) int {
+	switch {
+	case skipPath == skipPathAllDocuments:
+		return skipPrecedenceAll
+	case strings.HasPrefix(skipPath, skipSelectorModule):
+		return skipPrecedenceModule
+	case strings.HasPrefix(skipPath, skipSelectorType):
+		return skipPrecedenceType
+	case strings.HasPrefix(skipPath, skipSelectorRegexp):
+		return skipPrecedenceRegexp
+	case isSkipGlob(skipPath):
+		return skipPrecedenceGlob
+	}
+	return skipPrecedencePath
+}
+
+// orderedSkipPaths returns the lint.skip keys in precedence order: exact
+// paths, globs, re: expressions, type: and module: selectors, then "*".
+// Within a kind, longer keys are more specific and come first.
+func orderedSkipPaths(skip map[string][]ConfigSkipRule) []string {
+	paths := make([]string, 0, len(skip))
+	for skipPath := range skip {
+		paths = append(paths, skipPath)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		pi, pj := skipPathPrecedence(paths[i]), skipPathPrecedence(paths[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) > len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+	return paths
+}
+
+// skipPattern compiles a glob or re: key into a regular expression.
+func skipPattern(skipPath string) (*regexp.Regexp, error) {
+	if cached, ok := skipPatterns.Load(skipPath); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	var expression string
+	if strings.HasPrefix(skipPath, skipSelectorRegexp) {
+		expression = strings.TrimPrefix(skipPath, skipSelectorRegexp)
+	} else {
+		expression = skipGlobExpression(skipPath)
+	}
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lint.skip key %q: %w", skipPath, err)
+	}
+	skipPatterns.Store(skipPath, re)
+	return re, nil
+}
+
+// skipGlobExpression converts a glob into an anchored regular expression.
+// "*" and "?" do not cross folders; "**" matches any number of folders and
+// "[!...]" matches a character not in the class.
+func skipGlobExpression(glob string) string {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			builder.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			builder.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			builder.WriteString("[^/]*")
+		case glob[i] == '?':
+			builder.WriteString("[^/]")
+		case glob[i] == '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				builder.WriteString(regexp.QuoteMeta(glob[i:]))
+				i = len(glob)
+				continue
+			}
+			// A leading "!" negates the class, as in shell globs.
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + strings.TrimPrefix(class, "!")
+			}
+			builder.WriteString("[" + class + "]")
+			i += end
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	builder.WriteString("$")
+	return builder.String()
+}
+
+// validateSkipPath rejects lint.skip keys that can never match.
+func validateSkipPath(skipPath string) error {
+	for _, prefix := range []string{skipSelectorModule, skipSelectorType} {
+		if strings.HasPrefix(skipPath, prefix) && strings.TrimSpace(strings.TrimPrefix(skipPath, prefix)) == "" {
+			return fmt.Errorf("invalid lint.skip key %q: expected a name after %s", skipPath, prefix)
+		}
+	}
+	if strings.HasPrefix(skipPath, skipSelectorRegexp) || isSkipGlob(skipPath) {
+		if _, err := skipPattern(skipPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipDocument holds the path forms of a document that lint.skip keys are
+// matched against.
+type skipDocument struct {
+	// candidates are the forms exact path keys are compared with.
+	candidates []string
+	// relPath is the slash-separated path relative to the modelsource that
+	// globs and selectors are matched against.
+	relPath string
+}
+
+func newSkipDocument(inputFilePath string, modelSourcePath string) skipDocument {
+	relPath := normalizeSkipPath(inputFilePath)
+	if modelSourcePath != "" {
+		if rel, err := filepath.Rel(modelSourcePath, inputFilePath); err == nil && !strings.HasPrefix(filepath.ToSlash(rel), "../") {
+			relPath = normalizeSkipPath(rel)
+		}
+	}
+	return skipDocument{
+		candidates: buildSkipPathCandidates(inputFilePath, modelSourcePath),
+		relPath:    relPath,
+	}
+}
+
+// skipPathMatches reports whether a lint.skip key applies to a document.
+func skipPathMatches(skipPath string, inputFilePath string, modelSourcePath string) bool {
+	return newSkipDocument(inputFilePath, modelSourcePath).matches(skipPath)
+}
+
+func (d skipDocument) matches(skipPath string) bool {
+	switch skipPathPrecedence(skipPath) {
+	case skipPrecedenceAll:
+		return true
+	case skipPrecedenceModule:
+		module := strings.TrimSpace(strings.TrimPrefix(skipPath, skipSelectorModule))
+		return strings.HasPrefix(d.relPath, module+"/")
+	case skipPrecedenceType:
+		documentType := strings.TrimSpace(strings.TrimPrefix(skipPath, skipSelectorType))
+		name := strings.TrimSuffix(path.Base(d.relPath), ".yaml")
+		return name == documentType || strings.HasSuffix(name, "."+documentType)
+	case skipPrecedenceRegexp, skipPrecedenceGlob:
+		re, err := skipPattern(skipPath)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(d.relPath) || re.MatchString(strings.TrimSuffix(d.relPath, ".yaml"))
+	}
+	return slices.Contains(d.candidates, skipPath)
+}
+
+// warnUnmatchedSkipPaths warns about lint.skip keys that match no document of
+// the modelsource, which usually means a renamed or deleted document.
+func warnUnmatchedSkipPaths(index *modelIndex) {
+	_, skipPaths := getSkipConfig()
+	if len(skipPaths) == 0 {
+		return
+	}
+	documents := make([]skipDocument, len(index.documents))
+	for i, document := range index.documents {
+		documents[i] = newSkipDocument(document.path, index.root)
+	}
+	for _, skipPath := range skipPaths {
+		matched := false
+		for _, document := range documents {
+			if document.matches(skipPath) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			log.Warnf("lint.skip %s matches no document in %s", skipPath, index.root)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkipPathMatches(t *testing.T) {
+	modelSource := "/tmp/modelsource"
+	microflow := "/tmp/modelsource/Administration/Microflows/ACT_Account_Save.Microflows$Microflow.yaml"
+	page := "/tmp/modelsource/MyFirstModule/Pages/Home.Forms$Page.yaml"
+	tests := []struct {
+		skipPath string
+		path     string
+		expected bool
+	}{
+		{skipPath: "Administration/**", path: microflow, expected: true},
+		{skipPath: "Administration/**", path: page, expected: false},
+		{skipPath: "Administration/*", path: microflow, expected: false},
+		{skipPath: "*/Microflows/ACT_*", path: microflow, expected: true},
+		{skipPath: "**/Home.Forms$Page", path: page, expected: true},
+		{skipPath: "*/Microflows/[!A]*", path: microflow, expected: false},
+		{skipPath: "*/Microflows/[!B]*", path: microflow, expected: true},
+		{skipPath: "*/Microflows/[AB]CT_*", path: microflow, expected: true},
+		{skipPath: "module:Administration", path: microflow, expected: true},
+		{skipPath: "module:Admin", path: microflow, expected: false},
+		{skipPath: "module:tmp", path: microflow, expected: false},
+		{skipPath: "type:Microflows$Microflow", path: microflow, expected: true},
+		{skipPath: "type:Microflows$Microflow", path: page, expected: false},
+		{skipPath: "re:^MyFirstModule/Pages/", path: page, expected: true},
+		{skipPath: "re:^MyFirstModule/Pages/", path: microflow, expected: false},
+		{skipPath: "MyFirstModule/Pages/Home.Forms$Page", path: page, expected: true},
+		{skipPath: skipPathAllDocuments, path: page, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.skipPath, func(t *testing.T) {
+			if matched := skipPathMatches(tt.skipPath, tt.path, modelSource); matched != tt.expected {
+				t.Fatalf("expected %v for %s, got %v", tt.expected, tt.path, matched)
+			}
+		})
+	}
+}
+
+func TestShouldSkipRule_SkipSelectorPrecedence(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{
+		Lint: ConfigLintSpec{
+			Skip: map[string][]ConfigSkipRule{
+				skipPathAllDocuments:           {{Rule: "001_0001", Reason: "all"}},
+				"module:Administration":        {{Rule: "001_0001", Reason: "module"}},
+				"type:Microflows$Microflow":    {{Rule: "001_0001", Reason: "type"}, {Rule: "001_0002", Reason: "type"}},
+				"Administration/**":            {{Rule: "001_0001", Reason: "glob"}},
+				"Administration/Microflows/**": {{Rule: "001_0001", Reason: "longer glob"}},
+				"Administration/Microflows/ACT_Account_Save.Microflows$Microflow": {{Rule: "001_0003", Reason: "path"}},
+			},
+		},
+	})
+
+	inputFilePath := "/tmp/modelsource/Administration/Microflows/ACT_Account_Save.Microflows$Microflow.yaml"
+	for ruleNumber, expected := range map[string]string{
+		"001_0001": "longer glob",
+		"001_0002": "type",
+		"001_0003": "path",
+	} {
+		skip, reason := shouldSkipRule("", ruleNumber, false, inputFilePath, "/tmp/modelsource")
+		if !skip || reason != expected {
+			t.Fatalf("rule %s: expected reason %q, got skip=%v reason=%q", ruleNumber, expected, skip, reason)
+		}
+	}
+
+	skip, reason := shouldSkipRule("", "001_0001", false, "/tmp/modelsource/MyFirstModule/Pages/Home.Forms$Page.yaml", "/tmp/modelsource")
+	if !skip || reason != "all" {
+		t.Fatalf("expected the \"*\" key to apply last, got skip=%v reason=%q", skip, reason)
+	}
+}
+
+func TestValidateSkipEntries_InvalidSelectors(t *testing.T) {
+	for _, skipPath := range []string{"re:(", "module:", "type: "} {
+		err := validateSkipEntries(map[string][]ConfigSkipRule{skipPath: {{Rule: "001_0001"}}})
+		if err == nil || !strings.Contains(err.Error(), "invalid lint.skip key") {
+			t.Fatalf("expected %q to be rejected, got: %v", skipPath, err)
+		}
+	}
+}
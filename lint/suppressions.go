@@ -0,0 +1,167 @@
+package lint
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Suppression states of a lint.skip entry.
+const (
+	SuppressionActive   = "active"
+	SuppressionExpiring = "expiring"
+	SuppressionExpired  = "expired"
+)
+
+// currentTime returns the time lint.skip expiry dates are compared with.
+var currentTime = time.Now
+
+// Suppression is one lint.skip entry with its state and the documents it
+// matches, as listed by `lint --list-suppressions`.
+type Suppression struct {
+	Path      string   `json:"path"`
+	Rule      string   `json:"rule"`
+	Reason    string   `json:"reason,omitempty"`
+	Expires   string   `json:"expires,omitempty"`
+	Status    string   `json:"status"`
+	Documents []string `json:"documents"`
+}
+
+func parseSkipExpires(value string) (time.Time, error) {
+	expires, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid lint.skip expires %q: expected a date such as 2026-12-31", value)
+	}
+	return expires, nil
+}
+
+// validateSkipEntries rejects lint.skip keys that can never match and entries
+// with an invalid expires date, so that a typo cannot turn a time-boxed
+// suppression into a permanent one.
+func validateSkipEntries(skip map[string][]ConfigSkipRule) error {
+	for documentPath, entries := range skip {
+		if err := validateSkipPath(documentPath); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if strings.TrimSpace(entry.Expires) == "" {
+				continue
+			}
+			if _, err := parseSkipExpires(entry.Expires); err != nil {
+				return fmt.Errorf("%w (lint.skip %s)", err, documentPath)
+			}
+		}
+	}
+	return nil
+}
+
+// skipEntryStatus returns the state of a lint.skip entry at now. An entry
+// applies through its expires date and is expiring within the last
+// warningDays days before it.
+func skipEntryStatus(entry ConfigSkipRule, now time.Time, warningDays int) string {
+	if strings.TrimSpace(entry.Expires) == "" {
+		return SuppressionActive
+	}
+	expires, err := parseSkipExpires(entry.Expires)
+	if err != nil {
+		// Rejected by validateSkipEntries before linting; keep the skip.
+		return SuppressionActive
+	}
+	today, _ := time.Parse(time.DateOnly, now.Format(time.DateOnly))
+	if today.After(expires) {
+		return SuppressionExpired
+	}
+	if warningDays > 0 && !today.Before(expires.AddDate(0, 0, -warningDays)) {
+		return SuppressionExpiring
+	}
+	return SuppressionActive
+}
+
+func skipEntryExpired(entry ConfigSkipRule) bool {
+	return skipEntryStatus(entry, currentTime(), 0) == SuppressionExpired
+}
+
+// logSkipExpiry warns once per lint run about expired lint.skip entries and
+// entries within lint.skipExpiryWarningDays of expiring.
+func logSkipExpiry() {
+	cfg, skipPaths := getSkipConfig()
+	now := currentTime()
+	warningDays := skipExpiryWarningDays()
+	for _, documentPath := range skipPaths {
+		for _, entry := range cfg.Lint.Skip[documentPath] {
+			switch skipEntryStatus(entry, now, warningDays) {
+			case SuppressionExpired:
+				log.Warnf("lint.skip %s rule %s expired on %s and no longer applies", documentPath, skipEntryRule(entry), strings.TrimSpace(entry.Expires))
+			case SuppressionExpiring:
+				log.Warnf("lint.skip %s rule %s expires on %s", documentPath, skipEntryRule(entry), strings.TrimSpace(entry.Expires))
+			}
+		}
+	}
+}
+
+// ListSuppressions returns every lint.skip entry with its state and the
+// modelsource documents it matches, in lint.skip precedence order.
+func ListSuppressions(modelSourcePath string) ([]Suppression, error) {
+	cfg, skipPaths := getSkipConfig()
+	if len(skipPaths) == 0 {
+		return []Suppression{}, nil
+	}
+	if err := validateSkipEntries(cfg.Lint.Skip); err != nil {
+		return nil, err
+	}
+	index, err := buildModelIndex(modelSourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	now := currentTime()
+	warningDays := skipExpiryWarningDays()
+	suppressions := make([]Suppression, 0)
+	for _, documentPath := range skipPaths {
+		documents := make([]string, 0)
+		for _, document := range index.documents {
+			if skipPathMatches(documentPath, document.path, modelSourcePath) {
+				documents = append(documents, formatTestcaseName(document.path, modelSourcePath))
+			}
+		}
+		for _, entry := range cfg.Lint.Skip[documentPath] {
+			suppressions = append(suppressions, Suppression{
+				Path:      documentPath,
+				Rule:      skipEntryRule(entry),
+				Reason:    formatConfigSkipReason(entry),
+				Expires:   strings.TrimSpace(entry.Expires),
+				Status:    skipEntryStatus(entry, now, warningDays),
+				Documents: documents,
+			})
+		}
+	}
+	return suppressions, nil
+}
+
+// PrintSuppressions writes suppressions in the console format of lint.
+func PrintSuppressions(w io.Writer, suppressions []Suppression) {
+	counts := map[string]int{}
+	for _, suppression := range suppressions {
+		counts[suppression.Status]++
+		expires := ""
+		if suppression.Expires != "" {
+			expires = " until " + suppression.Expires
+		}
+		fmt.Fprintf(w, "%s %s rule %s%s: %s\n", strings.ToUpper(suppression.Status), suppression.Path, suppression.Rule, expires, suppression.Reason)
+		if len(suppression.Documents) == 0 {
+			fmt.Fprintln(w, "  (no matching documents)")
+		}
+		for _, document := range suppression.Documents {
+			fmt.Fprintf(w, "  %s\n", document)
+		}
+	}
+	fmt.Fprintf(w, "\n%d suppressions: %d active, %d expiring, %d expired\n", len(suppressions), counts[SuppressionActive], counts[SuppressionExpiring], counts[SuppressionExpired])
+}
+
+func skipEntryRule(entry ConfigSkipRule) string {
+	if entry.Rule == "" {
+		return "*"
+	}
+	return entry.Rule
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func setCurrentTime(t *testing.T, date string) {
+	t.Helper()
+	now, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		t.Fatalf("Failed to parse date: %v", err)
+	}
+	previous := currentTime
+	currentTime = func() time.Time { return now }
+	t.Cleanup(func() {
+		currentTime = previous
+	})
+}
+
+func TestSkipEntryStatus(t *testing.T) {
+	now, _ := time.Parse(time.DateOnly, "2026-06-10")
+	tests := []struct {
+		name        string
+		expires     string
+		warningDays int
+		expected    string
+	}{
+		{name: "no expiry", expires: "", warningDays: 30, expected: SuppressionActive},
+		{name: "far future", expires: "2026-12-31", warningDays: 30, expected: SuppressionActive},
+		{name: "inside warning window", expires: "2026-07-01", warningDays: 30, expected: SuppressionExpiring},
+		{name: "no warning window", expires: "2026-07-01", warningDays: 0, expected: SuppressionActive},
+		{name: "expires today", expires: "2026-06-10", warningDays: 0, expected: SuppressionActive},
+		{name: "expired yesterday", expires: "2026-06-09", warningDays: 30, expected: SuppressionExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := skipEntryStatus(ConfigSkipRule{Rule: "001_0001", Expires: tt.expires}, now, tt.warningDays)
+			if status != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestShouldSkipRule_ExpiredConfigSkip(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{
+		Lint: ConfigLintSpec{
+			Skip: map[string][]ConfigSkipRule{
+				"example/doc": {{Rule: "001_0001", Reason: "accepted risk", Expires: "2026-06-30"}},
+			},
+		},
+	})
+
+	setCurrentTime(t, "2026-06-30")
+	skip, reason := shouldSkipRule("", "001_0001", false, "/tmp/modelsource/example/doc.yaml", "/tmp/modelsource")
+	if !skip || reason != "accepted risk (expires 2026-06-30)" {
+		t.Fatalf("expected skip to apply through its expiry date, got skip=%v reason=%q", skip, reason)
+	}
+	activeHash := computeCacheConfigHash()
+
+	setCurrentTime(t, "2026-07-01")
+	if skip, _ := shouldSkipRule("", "001_0001", false, "/tmp/modelsource/example/doc.yaml", "/tmp/modelsource"); skip {
+		t.Fatal("expected expired skip not to apply")
+	}
+	if computeCacheConfigHash() == activeHash {
+		t.Fatal("expected the cache config hash to change when a skip expires")
+	}
+}
+
+func TestValidateSkipEntries(t *testing.T) {
+	var config Config
+	if err := yaml.Unmarshal([]byte("lint:\n  skip:\n    example/doc:\n      - rule: \"001_0001\"\n        expires: 2026-12-31\n"), &config); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if err := validateSkipEntries(config.Lint.Skip); err != nil {
+		t.Fatalf("expected unquoted YAML date to be valid, got: %v", err)
+	}
+
+	err := validateSkipEntries(map[string][]ConfigSkipRule{"example/doc": {{Rule: "001_0001", Expires: "31-12-2026"}}})
+	if err == nil || !strings.Contains(err.Error(), "example/doc") {
+		t.Fatalf("expected invalid expires to be rejected, got: %v", err)
+	}
+}
+
+func TestListSuppressions(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	setCurrentTime(t, "2026-06-10")
+
+	modelDir := t.TempDir()
+	for _, name := range []string{"A.yaml", "B.yaml"} {
+		if err := writeTestFile(modelDir, name, "Name: Test\n"); err != nil {
+			t.Fatalf("Failed to write yaml file: %v", err)
+		}
+	}
+	warningDays := 30
+	SetConfig(&Config{
+		Lint: ConfigLintSpec{
+			SkipExpiryWarningDays: &warningDays,
+			Skip: map[string][]ConfigSkipRule{
+				"A":                  {{Rule: "001_0001", Reason: "legacy", Expires: "2026-07-01"}},
+				"B.yaml":             {{Rule: "001_0002", Expires: "2026-01-01"}},
+				skipPathAllDocuments: {{Rule: "001_0003", Reason: "not applicable"}},
+			},
+		},
+	})
+
+	suppressions, err := ListSuppressions(modelDir)
+	if err != nil {
+		t.Fatalf("Failed to list suppressions: %v", err)
+	}
+	if len(suppressions) != 3 {
+		t.Fatalf("expected 3 suppressions, got %+v", suppressions)
+	}
+	expected := []struct {
+		path      string
+		status    string
+		documents string
+	}{
+		{path: "B.yaml", status: SuppressionExpired, documents: "B.yaml"},
+		{path: "A", status: SuppressionExpiring, documents: "A.yaml"},
+		{path: skipPathAllDocuments, status: SuppressionActive, documents: "A.yaml,B.yaml"},
+	}
+	for i, want := range expected {
+		got := suppressions[i]
+		if got.Path != want.path || got.Status != want.status || strings.Join(got.Documents, ",") != want.documents {
+			t.Fatalf("suppression %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	var output bytes.Buffer
+	PrintSuppressions(&output, suppressions)
+	if !strings.Contains(output.String(), "EXPIRED B.yaml rule 001_0002 until 2026-01-01") || !strings.Contains(output.String(), "3 suppressions: 1 active, 1 expiring, 1 expired") {
+		t.Fatalf("unexpected output:\n%s", output.String())
+	}
+}
@@ -0,0 +1,54 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+)
+
+// failureTypeTimeout is the Failure.Type of evaluations stopped by
+// lint.ruleTimeout.
+const failureTypeTimeout = "Timeout"
+
+// errRuleTimeout interrupts JavaScript runtimes and wraps Rego cancellations
+// that exceed lint.ruleTimeout.
+var errRuleTimeout = errors.New("rule evaluation timed out")
+
+// interruptAfter interrupts vm once timeout has passed. The returned stop
+// function reports whether the deadline expired; an expired runtime may still
+// carry the interrupt and must not be reused.
+func interruptAfter(vm *sobek.Runtime, timeout time.Duration) func() bool {
+	if timeout <= 0 {
+		return func() bool { return false }
+	}
+	timer := time.AfterFunc(timeout, func() {
+		vm.Interrupt(errRuleTimeout)
+	})
+	return func() bool {
+		return !timer.Stop()
+	}
+}
+
+func isRuleTimeout(err error) bool {
+	var interrupted *sobek.InterruptedError
+	if errors.As(err, &interrupted) {
+		return interrupted.Value() == errRuleTimeout
+	}
+	return errors.Is(err, errRuleTimeout)
+}
+
+// newTimeoutTestcase records an evaluation that exceeded lint.ruleTimeout.
+func newTimeoutTestcase(rulePath string, inputFilePath string, duration time.Duration) *Testcase {
+	message := fmt.Sprintf("Rule %s timed out after %s on %s", rulePath, ruleTimeout(), inputFilePath)
+	log.Error(message)
+	return &Testcase{
+		Name: inputFilePath,
+		Time: float64(duration.Nanoseconds()) / 1e9, // convert to seconds
+		Failure: &Failure{
+			Message: message,
+			Type:    failureTypeTimeout,
+		},
+	}
+}
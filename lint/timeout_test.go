@@ -0,0 +1,121 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEvalTestsuite_JavascriptTimeout(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{Lint: ConfigLintSpec{RuleTimeout: "100ms"}})
+
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "Hang.yaml", "Name: Hang\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	if err := writeTestFile(modelDir, "Ok.yaml", "Name: Ok\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	rule := Rule{
+		Path:       writeTestRule(t, "loop.js", "function rule(input) { while (input.Name === \"Hang\") {} return { allow: true, errors: [] }; }"),
+		RuleNumber: "001_0001",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJavascript,
+	}
+
+	testsuite, err := evalTestsuite(rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("evalTestsuite returned error: %v", err)
+	}
+	if testsuite.Tests != 2 || testsuite.Failures != 1 {
+		t.Fatalf("expected one timeout and one pass, got %+v", testsuite)
+	}
+	failure := testsuite.Testcases[0].Failure
+	if failure == nil || failure.Type != failureTypeTimeout {
+		t.Fatalf("expected Timeout failure, got %+v", failure)
+	}
+	if !strings.Contains(failure.Message, rule.Path) || !strings.Contains(failure.Message, "Hang.yaml") {
+		t.Fatalf("expected message to name rule and document, got %q", failure.Message)
+	}
+	if testsuite.Testcases[1].Failure != nil {
+		t.Fatalf("expected the run to continue after a timeout, got %+v", testsuite.Testcases[1].Failure)
+	}
+}
+
+func TestEvalTestsuite_RegoTimeout(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+	})
+	SetConfig(&Config{Lint: ConfigLintSpec{RuleTimeout: "100ms"}})
+
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "Doc.yaml", "Name: Test\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	regoContent := `package test.slow
+
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains "never" if {
+    some i in numbers.range(1, 100000)
+    some j in numbers.range(1, 100000)
+    i * j == -1
+}
+`
+	rule := Rule{
+		Path:        writeTestRule(t, "slow.rego", regoContent),
+		RuleNumber:  "001_0001",
+		Pattern:     ".*\\.yaml",
+		PackageName: "test.slow",
+		Language:    LanguageRego,
+	}
+
+	testsuite, err := evalTestsuite(rule, modelDir, false, false, nil, nil)
+	if err != nil {
+		t.Fatalf("evalTestsuite returned error: %v", err)
+	}
+	if testsuite.Failures != 1 || testsuite.Testcases[0].Failure.Type != failureTypeTimeout {
+		t.Fatalf("expected Timeout failure, got %+v", testsuite.Testcases)
+	}
+}
+
+func TestEvalTestsuite_TimeoutIsNotCached(t *testing.T) {
+	t.Cleanup(func() {
+		SetConfig(&Config{})
+		SetCacheDirectory("")
+	})
+	SetConfig(&Config{Lint: ConfigLintSpec{RuleTimeout: "50ms"}})
+	cacheDir := t.TempDir()
+	SetCacheDirectory(cacheDir)
+
+	modelDir := t.TempDir()
+	if err := writeTestFile(modelDir, "Hang.yaml", "Name: Hang\n"); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+	rule := Rule{
+		Path:       writeTestRule(t, "loop.js", "function rule(input) { while (true) {} }"),
+		RuleNumber: "001_0001",
+		Pattern:    ".*\\.yaml",
+		Language:   LanguageJavascript,
+	}
+	if _, err := evalTestsuite(rule, modelDir, false, true, nil, nil); err != nil {
+		t.Fatalf("evalTestsuite returned error: %v", err)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("Failed to read cache directory: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			t.Fatalf("expected timeout not to be cached, found %s", filepath.Join(cacheDir, entry.Name()))
+		}
+	}
+}
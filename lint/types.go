@@ -9,22 +9,43 @@ type TestSuites struct {
 }
 
 type Testsuite struct {
-	XMLName   xml.Name   `xml:"testsuite" json:"-"`
-	Name      string     `xml:"name,attr" json:"name"`
-	Tests     int        `xml:"tests,attr" json:"tests"`
-	Failures  int        `xml:"failures,attr" json:"failures"`
-	Skipped   int        `xml:"skipped,attr" json:"skipped"`
-	Time      float64    `xml:"time,attr" json:"time"`
+	XMLName   xml.Name `xml:"testsuite" json:"-"`
+	Name      string   `xml:"name,attr" json:"name"`
+	Tests     int      `xml:"tests,attr" json:"tests"`
+	Failures  int      `xml:"failures,attr" json:"failures"`
+	Errors    int      `xml:"errors,attr" json:"errors"`
+	Skipped   int      `xml:"skipped,attr" json:"skipped"`
+	Warnings  int      `xml:"warnings,attr,omitempty" json:"warnings,omitempty"`
+	Baselined int      `xml:"baselined,attr,omitempty" json:"baselined,omitempty"`
+	Time      float64  `xml:"time,attr" json:"time"`
+	// Coverage is the percentage of a Rego rule evaluated by its test cases.
+	Coverage  *float64   `xml:"coverage,attr,omitempty" json:"coverage,omitempty"`
 	Testcases []Testcase `xml:"testcase" json:"testcases"`
 }
 
 type Testcase struct {
-	XMLName      xml.Name `xml:"testcase" json:"-"`
-	Name         string   `xml:"name,attr" json:"name"`
-	OriginalPath string   `xml:"originalPath,attr,omitempty" json:"originalPath,omitempty"`
-	Time         float64  `xml:"time,attr" json:"time"`
-	Failure      *Failure `xml:"failure,omitempty" json:"failure,omitempty"`
-	Skipped      *Skipped `xml:"skipped,omitempty" json:"skipped,omitempty"`
+	XMLName      xml.Name    `xml:"testcase" json:"-"`
+	Name         string      `xml:"name,attr" json:"name"`
+	OriginalPath string      `xml:"originalPath,attr,omitempty" json:"originalPath,omitempty"`
+	Time         float64     `xml:"time,attr" json:"time"`
+	Failure      *Failure    `xml:"failure,omitempty" json:"failure,omitempty"`
+	Error        *RuleError  `xml:"error,omitempty" json:"error,omitempty"`
+	Warning      *Warning    `xml:"warning,omitempty" json:"warning,omitempty"`
+	Skipped      *Skipped    `xml:"skipped,omitempty" json:"skipped,omitempty"`
+	Baselined    *Baselined  `xml:"baselined,omitempty" json:"baselined,omitempty"`
+	Violations   []Violation `xml:"-" json:"violations,omitempty"`
+}
+
+// Violation is a single error returned by a rule. Path is a JSON pointer into
+// the document; Line and Column are resolved from it.
+type Violation struct {
+	Message  string `json:"message"`
+	Path     string `json:"path,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Element  string `json:"element,omitempty"`
+	Document string `json:"document,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
 }
 
 type Failure struct {
@@ -33,14 +54,36 @@ type Failure struct {
 	Data    string `xml:",chardata" json:"-"`
 }
 
+// RuleError holds an evaluation that did not produce a result because the
+// rule itself is broken: it failed to load, threw, or returned a malformed
+// result.
+type RuleError struct {
+	Message string `xml:"message,attr" json:"message"`
+	Type    string `xml:"type,attr" json:"type"`
+}
+
+// Warning holds a failure of a rule whose severity is below lint.failOn.
+type Warning struct {
+	Message string `xml:"message,attr" json:"message"`
+	Type    string `xml:"type,attr" json:"type"`
+}
+
 type Skipped struct {
 	Message string `xml:"message,attr" json:"message"`
 }
 
+// Baselined holds the failure messages of a testcase that are recorded in
+// the lint baseline and therefore no longer count as failures.
+type Baselined struct {
+	Message string `xml:"message,attr" json:"message"`
+}
+
 const (
 	LanguageRego       = "rego"
 	LanguageJavascript = "javascript"
 	LanguageTypescript = "typescript"
+	LanguageYaml       = "yaml"
+	LanguageJSONSchema = "jsonschema"
 )
 
 type Rule struct {
@@ -55,4 +98,17 @@ type Rule struct {
 	Pattern     string `json:"pattern"`
 	PackageName string `json:"packageName"`
 	Language    string `json:"language"`
+	Scope       string `json:"scope,omitempty"`
+	// Settings holds the defaults declared in custom.settings.
+	Settings map[string]interface{} `json:"settings,omitempty"`
+	// Original holds the metadata values replaced by rules.overrides.
+	Original *RuleOriginal `json:"original,omitempty"`
+}
+
+// RuleOriginal keeps the rule metadata as declared in the rule file, for
+// auditing rules whose severity, category or remediation were overridden.
+type RuleOriginal struct {
+	Severity    string `json:"severity"`
+	Category    string `json:"category"`
+	Remediation string `json:"remediation"`
 }
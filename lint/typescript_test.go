@@ -33,12 +33,12 @@ function rule(input: Record<string, unknown> = {}) {
 	}
 
 	hash := hashRuleContent(content)
-	typescriptRuleCache.mu.Lock()
-	typescriptRuleCache.entries[rulePath] = typescriptRuleCacheEntry{
+	ruleBundleCache.mu.Lock()
+	ruleBundleCache.entries[rulePath] = ruleBundleCacheEntry{
 		hash: hash,
 		code: "cached",
 	}
-	typescriptRuleCache.mu.Unlock()
+	ruleBundleCache.mu.Unlock()
 
 	code, err := transpileTypescriptRule(rulePath)
 	if err != nil {
@@ -61,12 +61,12 @@ function rule(input: Record<string, unknown> = {}) { return { allow: true, error
 	}
 
 	oldHash := hashRuleContent(content)
-	typescriptRuleCache.mu.Lock()
-	typescriptRuleCache.entries[rulePath] = typescriptRuleCacheEntry{
+	ruleBundleCache.mu.Lock()
+	ruleBundleCache.entries[rulePath] = ruleBundleCacheEntry{
 		hash: oldHash,
 		code: "cached",
 	}
-	typescriptRuleCache.mu.Unlock()
+	ruleBundleCache.mu.Unlock()
 
 	updated := []byte(`const metadata = { title: "Test", description: "Updated", custom: { category: "Test", rulename: "TestRule", severity: "LOW", rulenumber: "000_0001", remediation: "None", input: ".*" } };
 function rule(input: Record<string, unknown> = {}) { return { allow: false, errors: ["fail"] }; }`)
@@ -83,9 +83,9 @@ function rule(input: Record<string, unknown> = {}) { return { allow: false, erro
 	}
 
 	newHash := hashRuleContent(updated)
-	typescriptRuleCache.mu.RLock()
-	cached, found := typescriptRuleCache.entries[rulePath]
-	typescriptRuleCache.mu.RUnlock()
+	ruleBundleCache.mu.RLock()
+	cached, found := ruleBundleCache.entries[rulePath]
+	ruleBundleCache.mu.RUnlock()
 	if !found {
 		t.Fatalf("expected cache entry to be updated")
 	}
@@ -89,11 +89,18 @@ func parseNoqaDirective(line string) (bool, []string, string) {
 }
 
 // shouldSkipRule checks if a specific rule should be skipped based on config file
-// entries (lint.skip). Documentation-based noqa directives are ignored.
+// entries (lint.skip) or a noqa directive in the document's Documentation.
+// ignoreNoqa disables the noqa directives but not lint.skip.
 func shouldSkipRule(documentation string, ruleNumber string, ignoreNoqa bool, inputFilePath string, modelSourcePath string) (bool, string) {
 	if configSkip, reason := shouldSkipByConfig(inputFilePath, ruleNumber, modelSourcePath); configSkip {
 		return true, reason
 	}
+	if ignoreNoqa {
+		return false, ""
+	}
+	if directive, found := findNoqaDirective(documentation, ruleNumber); found {
+		return true, formatNoqaSkipReason(directive)
+	}
 	return false, ""
 }
 
@@ -117,47 +124,3 @@ func quoteRegoMetadataRulenumber(content string) string {
 		return match
 	})
 }
-
-func expandPaths(pattern string, workingDirectory string) ([]string, error) {
-	// backwards compatible with old filepath.glob(...)
-	if !strings.HasPrefix(pattern, ".*") {
-		oldPattern := pattern
-		pattern = strings.ReplaceAll(pattern, "$", "\\$")
-		pattern = strings.ReplaceAll(pattern, ".", "\\.")
-		pattern = strings.ReplaceAll(pattern, "**", ".*")
-		log.Infof("Expanded old pattern: %v -> %v", oldPattern, pattern)
-	}
-	// First get all files recursively under working directory
-	var matches []string
-	err := filepath.Walk(workingDirectory, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		// Skip directories
-		if info.IsDir() {
-			return nil
-		}
-		// Get relative path from working directory
-		relPath, err := filepath.Rel(workingDirectory, path)
-		if err != nil {
-			return err
-		}
-		// Check if path matches pattern
-		matched, err := regexp.MatchString(pattern, relPath)
-		if err != nil {
-			log.Errorf("Error matching path %v against pattern %v: %v", relPath, pattern, err)
-			return err
-		}
-		if matched {
-			matches = append(matches, path)
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-	if len(matches) == 0 {
-		log.Warnf("No matches found for pattern %v ", pattern)
-	}
-	return matches, nil
-}
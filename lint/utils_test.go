@@ -104,18 +104,18 @@ func TestShouldSkipRule(t *testing.T) {
 		expectedReason string
 	}{
 		{
-			name:           "Documentation noqa ignored - skip all rules directive",
+			name:           "Skip all rules",
 			documentation:  "#noqa",
 			ruleNumber:     "001_0002",
-			expectedSkip:   false,
-			expectedReason: "",
+			expectedSkip:   true,
+			expectedReason: `Skipped by "#noqa" in Documentation`,
 		},
 		{
-			name:           "Documentation noqa ignored - specific rule match",
+			name:           "Skip specific rule - match",
 			documentation:  "#noqa:001_0002,001_0003",
 			ruleNumber:     "001_0002",
-			expectedSkip:   false,
-			expectedReason: "",
+			expectedSkip:   true,
+			expectedReason: `Skipped by "#noqa:001_0002,001_0003" in Documentation`,
 		},
 		{
 			name:           "Skip specific rule - no match",
@@ -125,11 +125,11 @@ func TestShouldSkipRule(t *testing.T) {
 			expectedReason: "",
 		},
 		{
-			name:           "Documentation noqa ignored - multiline",
+			name:           "Skip specific rule - multiline",
 			documentation:  "Some text\n#noqa:001_0002 reason\nMore text",
 			ruleNumber:     "001_0002",
-			expectedSkip:   false,
-			expectedReason: "",
+			expectedSkip:   true,
+			expectedReason: `Skipped by "#noqa:001_0002 reason" in Documentation`,
 		},
 		{
 			name:           "No noqa directive",
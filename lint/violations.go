@@ -0,0 +1,190 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseViolations converts the errors returned by a rule into violations.
+// Each error is either a plain message or an object with message, path,
+// severity, element and, for project-scoped rules, document.
+func parseViolations(errors []interface{}) []Violation {
+	violations := make([]Violation, 0, len(errors))
+	for _, err := range errors {
+		object, ok := err.(map[string]interface{})
+		if !ok {
+			violations = append(violations, Violation{Message: fmt.Sprintf("%s", err)})
+			continue
+		}
+		violation := Violation{
+			Message:  violationField(object, "message"),
+			Path:     violationField(object, "path"),
+			Severity: violationField(object, "severity"),
+			Element:  violationField(object, "element"),
+			Document: violationField(object, "document"),
+		}
+		if violation.Message == "" {
+			violation.Message = fmt.Sprintf("%v", err)
+		}
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+func violationField(object map[string]interface{}, key string) string {
+	value, ok := object[key]
+	if !ok || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// resolveViolationLocations sets the line and column of every violation with
+// a path from the parsed document. Violations without an element name get the
+// Name of the mapping the path points at, so reports can show which entity,
+// attribute or microflow object is meant.
+func resolveViolationLocations(node *yaml.Node, violations []Violation) {
+	if node == nil {
+		return
+	}
+	for i := range violations {
+		v := &violations[i]
+		if v.Path == "" {
+			continue
+		}
+		position, target := lookupJSONPointer(node, v.Path)
+		if position == nil {
+			continue
+		}
+		v.Line = position.Line
+		v.Column = position.Column
+		if v.Element == "" && target != nil && target.Kind == yaml.MappingNode {
+			if name := mappingValue(target, "Name"); name != nil && name.Kind == yaml.ScalarNode {
+				v.Element = name.Value
+			}
+		}
+	}
+}
+
+// lookupJSONPointer follows an RFC 6901 JSON pointer through a YAML node. It
+// returns the node to report the position of (the key node for mapping
+// entries) and the value node. When the pointer cannot be followed completely
+// the deepest resolved node is returned.
+func lookupJSONPointer(node *yaml.Node, pointer string) (*yaml.Node, *yaml.Node) {
+	current := node
+	if current.Kind == yaml.DocumentNode && len(current.Content) > 0 {
+		current = current.Content[0]
+	}
+	position := current
+
+	pointer = strings.TrimPrefix(pointer, "/")
+	if pointer == "" {
+		return position, current
+	}
+	for _, token := range strings.Split(pointer, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch current.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(current.Content); i += 2 {
+				if current.Content[i].Value == token {
+					position = current.Content[i]
+					current = current.Content[i+1]
+					found = true
+					break
+				}
+			}
+			if !found {
+				return position, nil
+			}
+		case yaml.SequenceNode:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(current.Content) {
+				return position, nil
+			}
+			current = current.Content[index]
+			position = current
+		default:
+			return position, nil
+		}
+	}
+	return position, current
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// newRuleResultTestcase builds the testcase of a rule result on one document.
+// Violations suppressed by noqa directives on nested elements are dropped; a
+// testcase whose violations are all suppressed is skipped.
+func newRuleResultTestcase(inputFilePath string, data map[string]interface{}, node *yaml.Node, ruleNumber string, ignoreNoqa bool, allow bool, errors []interface{}, duration time.Duration) *Testcase {
+	testcase := &Testcase{
+		Name: inputFilePath,
+		Time: float64(duration.Nanoseconds()) / 1e9, // convert to seconds
+	}
+	if allow {
+		return testcase
+	}
+	violations, suppressed := suppressNoqaViolations(data, parseViolations(errors), ruleNumber, ignoreNoqa)
+	if len(violations) == 0 && len(suppressed) > 0 {
+		testcase.Skipped = &Skipped{Message: strings.Join(suppressed, "; ")}
+		return testcase
+	}
+	resolveViolationLocations(node, violations)
+	testcase.Failure = newAssertionFailure(violations)
+	testcase.Violations = violations
+	return testcase
+}
+
+// newAssertionFailure joins violation messages into a failure.
+func newAssertionFailure(violations []Violation) *Failure {
+	messages := make([]string, 0, len(violations))
+	for _, v := range violations {
+		messages = append(messages, v.Message)
+	}
+	return &Failure{
+		Message: strings.Join(messages, "\n"),
+		Type:    "AssertionError",
+	}
+}
+
+// violationDetails lists the located violations of a testcase, one per line.
+// It becomes the xunit failure body so CI viewers can point at the exact element.
+func violationDetails(violations []Violation) string {
+	details := make([]string, 0)
+	for _, v := range violations {
+		if location := formatViolationLocation(v); location != "" {
+			details = append(details, location+": "+v.Message)
+		}
+	}
+	return strings.Join(details, "\n")
+}
+
+// formatViolationLocation formats a violation location as
+// "line:column path (element)", omitting unknown parts.
+func formatViolationLocation(v Violation) string {
+	parts := make([]string, 0, 3)
+	if v.Line > 0 {
+		parts = append(parts, fmt.Sprintf("%d:%d", v.Line, v.Column))
+	}
+	if v.Path != "" {
+		parts = append(parts, v.Path)
+	}
+	if v.Element != "" {
+		parts = append(parts, "("+v.Element+")")
+	}
+	return strings.Join(parts, " ")
+}
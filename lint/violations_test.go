@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const violationsDocument = `Name: DomainModel
+Entities:
+- Name: Customer
+  Attributes:
+  - Name: Id
+  - Name: Email
+- Name: Order
+  Attributes: []
+`
+
+func TestLookupJSONPointer(t *testing.T) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(violationsDocument), &node); err != nil {
+		t.Fatalf("Failed to parse yaml: %v", err)
+	}
+
+	tests := []struct {
+		pointer string
+		line    int
+		column  int
+	}{
+		{pointer: "", line: 1, column: 1},
+		{pointer: "/Entities", line: 2, column: 1},
+		{pointer: "/Entities/1", line: 7, column: 3},
+		{pointer: "/Entities/0/Attributes/1", line: 6, column: 5},
+		{pointer: "/Entities/0/Attributes/1/Name", line: 6, column: 5},
+		{pointer: "/Entities/5", line: 2, column: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			position, _ := lookupJSONPointer(&node, tt.pointer)
+			if position == nil || position.Line != tt.line || position.Column != tt.column {
+				t.Fatalf("expected %d:%d, got %+v", tt.line, tt.column, position)
+			}
+		})
+	}
+}
+
+func TestParseViolations(t *testing.T) {
+	violations := parseViolations([]interface{}{
+		"plain message",
+		map[string]interface{}{
+			"message":  "Attribute has no documentation",
+			"path":     "/Entities/0/Attributes/1",
+			"severity": "LOW",
+		},
+	})
+
+	if len(violations) != 2 || violations[0].Message != "plain message" || violations[0].Path != "" {
+		t.Fatalf("unexpected violations: %+v", violations)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(violationsDocument), &node); err != nil {
+		t.Fatalf("Failed to parse yaml: %v", err)
+	}
+	resolveViolationLocations(&node, violations)
+
+	v := violations[1]
+	if v.Line != 6 || v.Column != 5 || v.Element != "Email" || v.Severity != "LOW" {
+		t.Fatalf("expected resolved location for Email attribute, got %+v", v)
+	}
+	if got := violationDetails(violations); got != "6:5 /Entities/0/Attributes/1 (Email): Attribute has no documentation" {
+		t.Fatalf("unexpected violation details: %q", got)
+	}
+}
+
+func TestEvalTestcase_StructuredViolations(t *testing.T) {
+	rulesDir := t.TempDir()
+	modelDir := t.TempDir()
+	inputPath := filepath.Join(modelDir, "DomainModels$DomainModel.yaml")
+	if err := os.WriteFile(inputPath, []byte(violationsDocument), 0644); err != nil {
+		t.Fatalf("Failed to write yaml file: %v", err)
+	}
+
+	t.Run("rego", func(t *testing.T) {
+		rulePath := filepath.Join(rulesDir, "099_0020_empty_entities.rego")
+		regoContent := `# METADATA
+# title: Entities have attributes
+# description: Entities without attributes are not useful
+# custom:
+#  rulenumber: 099_0020
+#  input: .*DomainModel\.yaml
+package test.empty_entities
+import rego.v1
+
+default allow := false
+allow if count(errors) == 0
+
+errors contains error if {
+    some i, entity in input.Entities
+    count(entity.Attributes) == 0
+    error := {"message": sprintf("Entity %v has no attributes", [entity.Name]), "path": sprintf("/Entities/%d", [i])}
+}
+`
+		if err := os.WriteFile(rulePath, []byte(regoContent), 0644); err != nil {
+			t.Fatalf("Failed to write rego file: %v", err)
+		}
+
+		testcase, err := evalTestcase_Rego(rulePath, "data.test.empty_entities", inputPath, "099_0020", false, modelDir, nil)
+		if err != nil {
+			t.Fatalf("Failed to evaluate rule: %v", err)
+		}
+		if testcase.Failure == nil || testcase.Failure.Message != "Entity Order has no attributes" {
+			t.Fatalf("expected failure message from structured error, got %+v", testcase.Failure)
+		}
+		if len(testcase.Violations) != 1 || testcase.Violations[0].Line != 7 || testcase.Violations[0].Element != "Order" {
+			t.Fatalf("expected located violation, got %+v", testcase.Violations)
+		}
+	})
+
+	t.Run("javascript", func(t *testing.T) {
+		rulePath := filepath.Join(rulesDir, "099_0021_attribute_names.js")
+		jsContent := `
+const metadata = {
+    title: "Attribute names",
+    description: "Attribute names must not be Id",
+    custom: {
+        rulenumber: "099_0021",
+        input: ".*DomainModel\\.yaml"
+    }
+};
+
+function rule(input) {
+    const errors = [];
+    input.Entities.forEach((entity, i) => {
+        entity.Attributes.forEach((attribute, j) => {
+            if (attribute.Name === "Id") {
+                errors.push({ message: "Attribute Id is reserved", path: "/Entities/" + i + "/Attributes/" + j, element: entity.Name + ".Id" });
+            }
+        });
+    });
+    return { allow: errors.length === 0, errors: errors };
+}
+`
+		if err := os.WriteFile(rulePath, []byte(jsContent), 0644); err != nil {
+			t.Fatalf("Failed to write js file: %v", err)
+		}
+
+		testcase, err := evalTestcase_Javascript(rulePath, inputPath, "099_0021", false, modelDir, nil)
+		if err != nil {
+			t.Fatalf("Failed to evaluate rule: %v", err)
+		}
+		if testcase.Failure == nil || len(testcase.Violations) != 1 {
+			t.Fatalf("expected one violation, got %+v", testcase)
+		}
+		v := testcase.Violations[0]
+		if v.Line != 5 || v.Column != 5 || v.Element != "Customer.Id" {
+			t.Fatalf("expected located violation with explicit element, got %+v", v)
+		}
+	})
+}
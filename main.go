@@ -107,6 +107,18 @@ func main() {
 				log.SetLevel(logrus.InfoLevel)
 			}
 			lint.SetLogger(log)
+			if err := applyRuleSelectionFlags(cmd, config); err != nil {
+				log.Errorf("%s", err)
+				os.Exit(1)
+			}
+			if cmd.Flags().Changed("fail-on-rule-error") {
+				failOnRuleError, err := cmd.Flags().GetBool("fail-on-rule-error")
+				if err != nil {
+					log.Errorf("failed to read --fail-on-rule-error flag: %s", err)
+					os.Exit(1)
+				}
+				config.Lint.FailOnRuleError = &failOnRuleError
+			}
 			lint.SetConfig(config)
 			configureCache(config, projectDir)
 
@@ -120,6 +132,21 @@ func main() {
 				modelDirectory = filepath.Join(projectDir, modelDirectory)
 			}
 
+			listSuppressions, err := cmd.Flags().GetBool("list-suppressions")
+			if err != nil {
+				log.Errorf("failed to read --list-suppressions flag: %s", err)
+				os.Exit(1)
+			}
+			if listSuppressions {
+				suppressions, err := lint.ListSuppressions(modelDirectory)
+				if err != nil {
+					log.Errorf("failed to list suppressions: %s", err)
+					os.Exit(1)
+				}
+				lint.PrintSuppressions(os.Stdout, suppressions)
+				return
+			}
+
 			if config != nil && len(config.Rules.Rulesets) > 0 {
 				log.Infof("Syncing %d rulesets to %s", len(config.Rules.Rulesets), rulesDirectory)
 				if err := lint.SyncRulesets(config.Rules.Rulesets, rulesDirectory, projectDir); err != nil {
@@ -134,6 +161,38 @@ func main() {
 				os.Exit(1)
 			}
 
+			writeBaseline, err := cmd.Flags().GetBool("write-baseline")
+			if err != nil {
+				log.Errorf("failed to read --write-baseline flag: %s", err)
+				os.Exit(1)
+			}
+			if writeBaseline {
+				if diffOnly {
+					log.Errorf("--write-baseline cannot be combined with --diff; the baseline must cover the whole modelsource")
+					os.Exit(1)
+				}
+				baselinePath := strings.TrimSpace(config.Lint.Baseline)
+				if baselinePath == "" {
+					baselinePath = lint.DefaultBaselineFile
+				}
+				count, err := lint.WriteBaseline(
+					rulesDirectory,
+					modelDirectory,
+					baselinePath,
+					boolValue(config.Lint.IgnoreNoqa, false),
+					effectiveLintUseCache(config),
+				)
+				if err != nil {
+					log.Errorf("failed to write baseline: %s", err)
+					os.Exit(1)
+				}
+				log.Infof("Recorded %d violation(s) in baseline %s", count, baselinePath)
+				if strings.TrimSpace(config.Lint.Baseline) == "" {
+					log.Infof("Set lint.baseline to %s so later lint runs only fail on new violations", baselinePath)
+				}
+				return
+			}
+
 			var changedFiles []string
 			if diffOnly {
 				changedFiles, err = lint.GitUnstagedChangedFiles(modelDirectory)
@@ -162,6 +221,7 @@ func main() {
 				modelDirectory,
 				config.Lint.XunitReport,
 				config.Lint.JSONFile,
+				config.Lint.SarifFile,
 				boolValue(config.Lint.IgnoreNoqa, false),
 				effectiveLintUseCache(config),
 				changedFiles,
@@ -173,6 +233,12 @@ func main() {
 		},
 	}
 	cmdLint.Flags().Bool("diff", false, "Only lint model documents with unstaged or untracked changes in the modelsource git repository")
+	cmdLint.Flags().Bool("write-baseline", false, "Record all current violations in the lint.baseline file instead of failing on them")
+	cmdLint.Flags().StringSlice("rule", nil, "Only evaluate rules whose number matches this glob (repeatable, replaces rules.include)")
+	cmdLint.Flags().StringSlice("category", nil, "Only evaluate rules in this category (repeatable, replaces rules.include)")
+	cmdLint.Flags().String("min-severity", "", "Only evaluate rules with at least this severity: LOW, MEDIUM or HIGH (overrides rules.minSeverity)")
+	cmdLint.Flags().Bool("list-suppressions", false, "List the lint.skip entries with their expiry state and matching documents instead of linting")
+	cmdLint.Flags().Bool("fail-on-rule-error", true, "Fail when a rule cannot be evaluated because it does not load, throws or returns a malformed result (overrides lint.failOnRuleError)")
 	rootCmd.AddCommand(cmdLint)
 
 	var cmdInit = &cobra.Command{
@@ -344,15 +410,206 @@ func main() {
 				log.SetLevel(logrus.InfoLevel)
 			}
 			lint.SetLogger(log)
-			err = lint.TestAll(config.Rules.Path)
+			xunitReport, err := cmd.Flags().GetString("xunit-report")
+			if err != nil {
+				log.Errorf("Failed to read xunit-report flag: %s", err)
+				os.Exit(1)
+			}
+			coverageThreshold := 0.0
+			if config.Rules.CoverageThreshold != nil {
+				coverageThreshold = *config.Rules.CoverageThreshold
+			}
+			if cmd.Flags().Changed("coverage-threshold") {
+				coverageThreshold, err = cmd.Flags().GetFloat64("coverage-threshold")
+				if err != nil {
+					log.Errorf("Failed to read coverage-threshold flag: %s", err)
+					os.Exit(1)
+				}
+			}
+			err = lint.TestAllWithReport(config.Rules.Path, xunitReport, coverageThreshold)
 			if err != nil {
 				log.Errorf("Test rules failed: %s", err)
 				os.Exit(1)
 			}
 		},
 	}
+	cmdRules.Flags().String("xunit-report", "", "Path of an xunit report with a testsuite per rule and a testcase per test case")
+	cmdRules.Flags().Float64("coverage-threshold", 0, "Fail Rego rules whose coverage by their test cases is below this percentage (overrides rules.coverageThreshold)")
 	rootCmd.AddCommand(cmdRules)
 
+	var cmdRuleset = &cobra.Command{
+		Use:   "rules",
+		Short: "Manage the rules of the rules directory",
+	}
+	var cmdRulesValidate = &cobra.Command{
+		Use:   "validate",
+		Short: "Check rules for metadata, pattern and contract problems",
+		Long:  "Checks every rule for the required metadata, a known severity, a compilable input pattern, a package declaration, a unique rule number in the NNN_NNNN format and test cases, without running the rules. Rules whose input matches no document of the modelsource are reported as warnings. Exits with an error when any check fails.",
+		Run: func(cmd *cobra.Command, args []string) {
+			projectDir, err := os.Getwd()
+			if err != nil {
+				fmt.Printf("failed to resolve current working directory: %s\n", err)
+				os.Exit(1)
+			}
+			config, err := lint.LoadMergedConfigFromPath(projectDir, configPathForCommand(cmd))
+			if err != nil {
+				fmt.Printf("failed to load configuration: %s\n", err)
+				os.Exit(1)
+			}
+			log := logrus.New()
+			if isVerbose(cmd) {
+				log.SetLevel(logrus.DebugLevel)
+			} else {
+				log.SetLevel(logrus.WarnLevel)
+			}
+			lint.SetLogger(log)
+
+			format, err := cmd.Flags().GetString("format")
+			if err != nil {
+				log.Errorf("failed to read --format flag: %s", err)
+				os.Exit(1)
+			}
+			if format != "text" && format != "json" {
+				log.Errorf("unknown format %q (expected text or json)", format)
+				os.Exit(1)
+			}
+			modelDirectory := config.Modelsource
+			if !filepath.IsAbs(modelDirectory) {
+				modelDirectory = filepath.Join(projectDir, modelDirectory)
+			}
+
+			validation, err := lint.ValidateRules(config.Rules.Path, modelDirectory)
+			if err != nil {
+				log.Errorf("failed to validate rules: %s", err)
+				os.Exit(1)
+			}
+			if format == "json" {
+				err = validation.WriteJSON(os.Stdout)
+			} else {
+				err = validation.WriteText(os.Stdout)
+			}
+			if err != nil {
+				log.Errorf("failed to write validation: %s", err)
+				os.Exit(1)
+			}
+			if validation.Errors > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	cmdRulesValidate.Flags().String("format", "text", "Output format: text or json")
+	cmdRuleset.AddCommand(cmdRulesValidate)
+	rootCmd.AddCommand(cmdRuleset)
+
+	var cmdTypes = &cobra.Command{
+		Use:   "types",
+		Short: "Generate TypeScript type definitions for the exported model",
+		Long:  "Scans the exported modelsource and writes a .d.ts file with an interface per $Type, merged across all documents and nested elements, and declarations for the mxlint API and the rule contract. Reference it from JavaScript and TypeScript rules to type-check them in an editor.",
+		Run: func(cmd *cobra.Command, args []string) {
+			projectDir, err := os.Getwd()
+			if err != nil {
+				fmt.Printf("failed to resolve current working directory: %s\n", err)
+				os.Exit(1)
+			}
+			config, err := lint.LoadMergedConfigFromPath(projectDir, configPathForCommand(cmd))
+			if err != nil {
+				fmt.Printf("failed to load configuration: %s\n", err)
+				os.Exit(1)
+			}
+			log := logrus.New()
+			if isVerbose(cmd) {
+				log.SetLevel(logrus.DebugLevel)
+			} else {
+				log.SetLevel(logrus.InfoLevel)
+			}
+			lint.SetLogger(log)
+
+			modelDirectory := config.Modelsource
+			if !filepath.IsAbs(modelDirectory) {
+				modelDirectory = filepath.Join(projectDir, modelDirectory)
+			}
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				log.Errorf("failed to read --output flag: %s", err)
+				os.Exit(1)
+			}
+
+			documents, interfaces, err := lint.GenerateTypes(modelDirectory, output)
+			if err != nil {
+				log.Errorf("failed to generate types: %s", err)
+				os.Exit(1)
+			}
+			log.Infof("Wrote %d interface(s) from %d document(s) to %s", interfaces, documents, output)
+		},
+	}
+	cmdTypes.Flags().StringP("output", "o", lint.DefaultTypesFile, "Path of the .d.ts file to write")
+	rootCmd.AddCommand(cmdTypes)
+
+	var cmdNewRule = &cobra.Command{
+		Use:   "new-rule",
+		Short: "Generate a new rule with its test cases",
+		Long:  "Writes a Rego, JavaScript or TypeScript rule with complete metadata and a skeleton rule(), numbered after the last rule of the category in the rules directory, and a _test.yaml with a passing and a failing case built from a matching document of the modelsource.",
+		Run: func(cmd *cobra.Command, args []string) {
+			projectDir, err := os.Getwd()
+			if err != nil {
+				fmt.Printf("failed to resolve current working directory: %s\n", err)
+				os.Exit(1)
+			}
+			config, err := lint.LoadMergedConfigFromPath(projectDir, configPathForCommand(cmd))
+			if err != nil {
+				fmt.Printf("failed to load configuration: %s\n", err)
+				os.Exit(1)
+			}
+			log := logrus.New()
+			if isVerbose(cmd) {
+				log.SetLevel(logrus.DebugLevel)
+			} else {
+				log.SetLevel(logrus.InfoLevel)
+			}
+			lint.SetLogger(log)
+
+			modelDirectory := config.Modelsource
+			if !filepath.IsAbs(modelDirectory) {
+				modelDirectory = filepath.Join(projectDir, modelDirectory)
+			}
+			options := lint.NewRuleOptions{
+				RulesPath:       config.Rules.Path,
+				ModelSourcePath: modelDirectory,
+			}
+			for flag, value := range map[string]*string{
+				"lang":        &options.Language,
+				"category":    &options.Category,
+				"input":       &options.Input,
+				"title":       &options.Title,
+				"description": &options.Description,
+				"severity":    &options.Severity,
+				"remediation": &options.Remediation,
+			} {
+				if *value, err = cmd.Flags().GetString(flag); err != nil {
+					log.Errorf("failed to read --%s flag: %s", flag, err)
+					os.Exit(1)
+				}
+			}
+
+			rule, testPath, err := lint.NewRule(options)
+			if err != nil {
+				log.Errorf("failed to generate rule: %s", err)
+				os.Exit(1)
+			}
+			log.Infof("Wrote rule %s to %s", rule.RuleNumber, rule.Path)
+			log.Infof("Wrote test cases to %s", testPath)
+		},
+	}
+	cmdNewRule.Flags().String("lang", "rego", "Language of the rule: rego, js or ts")
+	cmdNewRule.Flags().String("category", "", "Category of the rule; the rule number continues the numbering of the category")
+	cmdNewRule.Flags().String("input", ".*\\.yaml", "Regular expression on the document paths the rule checks")
+	cmdNewRule.Flags().String("title", "New rule", "Title of the rule, also used for the file name and rulename")
+	cmdNewRule.Flags().String("description", "", "Description of the rule (default the title)")
+	cmdNewRule.Flags().String("severity", lint.SeverityMedium, "Severity of the rule: LOW, MEDIUM or HIGH")
+	cmdNewRule.Flags().String("remediation", "Describe how to fix a violation", "Remediation of the rule")
+	cmdNewRule.MarkFlagRequired("category")
+	rootCmd.AddCommand(cmdNewRule)
+
 	var cmdCacheClear = &cobra.Command{
 		Use:   "cache-clear",
 		Short: "Clear the lint results cache",
@@ -472,6 +729,33 @@ func boolValue(value *bool, fallback bool) bool {
 	return *value
 }
 
+// applyRuleSelectionFlags overrides the configured rule selection with the
+// lint --rule, --category and --min-severity flags: --rule and --category
+// replace rules.include and --min-severity replaces rules.minSeverity, while
+// rules.exclude still applies.
+func applyRuleSelectionFlags(cmd *cobra.Command, config *lint.Config) error {
+	rules, err := cmd.Flags().GetStringSlice("rule")
+	if err != nil {
+		return fmt.Errorf("failed to read --rule flag: %w", err)
+	}
+	categories, err := cmd.Flags().GetStringSlice("category")
+	if err != nil {
+		return fmt.Errorf("failed to read --category flag: %w", err)
+	}
+	minSeverity, err := cmd.Flags().GetString("min-severity")
+	if err != nil {
+		return fmt.Errorf("failed to read --min-severity flag: %w", err)
+	}
+
+	if selectors := lint.CommandLineRuleSelectors(rules, categories); len(selectors) > 0 {
+		config.Rules.Include = selectors
+	}
+	if strings.TrimSpace(minSeverity) != "" {
+		config.Rules.MinSeverity = strings.TrimSpace(minSeverity)
+	}
+	return nil
+}
+
 func effectiveLintUseCache(config *lint.Config) bool {
 	if config == nil {
 		return true
@@ -0,0 +1,16 @@
+package app.mendix.project_settings.security_checks_test
+import rego.v1
+
+import data.app.mendix.project_settings.security_checks
+
+test_allow_production_security if {
+    security_checks.allow with input as {"CheckSecurity": true, "SecurityLevel": "CheckEverything"}
+}
+
+test_no_allow_without_security_check if {
+    not security_checks.allow with input as {"CheckSecurity": false, "SecurityLevel": "CheckEverything"}
+}
+
+test_error_per_problem if {
+    count(security_checks.errors) == 2 with input as {"CheckSecurity": false, "SecurityLevel": "Prototype"}
+}
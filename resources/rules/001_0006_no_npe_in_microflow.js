@@ -1,3 +1,5 @@
+import { isNPE } from "lib/domain_model";
+
 const metadata = {
     scope: "package",
     title: "No NPE in microflow",
@@ -13,31 +15,6 @@ const metadata = {
     }
 };
 
-function entityName(entityRef) {
-    const parts = entityRef.split(".");
-    return parts[parts.length - 1];
-}
-
-function isNPE(entity) {
-    // read the parent domain model and check if the entity is NPE
-    // entity: Module2.EntityNonPersist
-    if (entity === undefined) {
-        return false;
-    }
-    const moduleName = entity.split(".")[0];
-    const domainModelPath = [moduleName, "DomainModels$DomainModel.yaml"].join("/");
-    const domainModel = mxlint.io.readYaml(domainModelPath);
-    if (domainModel === undefined || domainModel.Entities === undefined) {
-        return false;
-    }
-    const name = entityName(entity);
-    const found = domainModel.Entities.find(e => e.Name === name);
-    if (found === undefined || found.MaybeGeneralization === undefined) {
-        return false;
-    }
-    return found.MaybeGeneralization.Persistable === false;
-}
-
 function rule(input = {}) {
     const errors = [];
 
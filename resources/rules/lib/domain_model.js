@@ -0,0 +1,26 @@
+// Helpers shared by the domain model rules.
+
+export function entityName(entityRef) {
+    const parts = entityRef.split(".");
+    return parts[parts.length - 1];
+}
+
+export function isNPE(entity) {
+    // read the parent domain model and check if the entity is NPE
+    // entity: Module2.EntityNonPersist
+    if (entity === undefined) {
+        return false;
+    }
+    const moduleName = entity.split(".")[0];
+    const domainModelPath = [moduleName, "DomainModels$DomainModel.yaml"].join("/");
+    const domainModel = mxlint.io.readYaml(domainModelPath);
+    if (domainModel === undefined || domainModel.Entities === undefined) {
+        return false;
+    }
+    const name = entityName(entity);
+    const found = domainModel.Entities.find(e => e.Name === name);
+    if (found === undefined || found.MaybeGeneralization === undefined) {
+        return false;
+    }
+    return found.MaybeGeneralization.Persistable === false;
+}
@@ -243,6 +243,7 @@ func runServe(cmd *cobra.Command, args []string) {
 				outputDirectory,
 				"",
 				"",
+				"",
 				boolValue(config.Lint.IgnoreNoqa, false),
 				effectiveLintUseCacheForServe(config),
 				nil,
@@ -78,6 +78,10 @@ const dashboardTemplate = `
             background-color: #fff5f5;
             border-left: 3px solid #d73a49;
         }
+        .testcase-warn {
+            background-color: #fffbea;
+            border-left: 3px solid #e36209;
+        }
         .testcase-skip {
             background-color: #f8f8f8;
             border-left: 3px solid #6a737d;
@@ -94,6 +98,14 @@ const dashboardTemplate = `
             font-family: monospace;
             white-space: pre-wrap;
         }
+        .violation-list {
+            margin: 5px 0 0 0;
+            padding-left: 20px;
+            font-family: monospace;
+        }
+        .violation-location {
+            color: #6a737d;
+        }
         .refresh-button {
             background-color: #0066cc;
             color: white;
@@ -211,13 +223,38 @@ const dashboardTemplate = `
                 {{if eq $testsuite.Name $rulePath}}
                     <h4>Test Results</h4>
                     {{range $testsuite.Testcases}}
-                        {{if .Failure}}
+                        {{if .Error}}
+                            <div class="testcase testcase-fail result-item result-failure">
+                                <div class="testcase-header">
+                                    <div>❗ {{.Name}}</div>
+                                    <div>{{.Error.Type}}</div>
+                                </div>
+                                <div class="failure-message">{{.Error.Message}}</div>
+                            </div>
+                        {{else if .Failure}}
                             <div class="testcase testcase-fail result-item result-failure">
                                 <div class="testcase-header">
                                     <div>❌ {{.Name}}</div>
                                     <div>{{printf "%.3fs" .Time}}</div>
                                 </div>
                                 <div class="failure-message">{{.Failure.Message}}</div>
+                                {{if .Violations}}
+                                <ul class="violation-list">
+                                    {{range .Violations}}
+                                    {{if .Path}}
+                                    <li><span class="violation-location">{{if .Line}}line {{.Line}}:{{.Column}} {{end}}{{.Path}}{{if .Element}} ({{.Element}}){{end}}</span> {{.Message}}</li>
+                                    {{end}}
+                                    {{end}}
+                                </ul>
+                                {{end}}
+                            </div>
+                        {{else if .Warning}}
+                            <div class="testcase testcase-warn result-item result-failure">
+                                <div class="testcase-header">
+                                    <div>⚠️ {{.Name}}</div>
+                                    <div>{{printf "%.3fs" .Time}}</div>
+                                </div>
+                                <div class="failure-message">{{.Warning.Message}}</div>
                             </div>
                         {{else if .Skipped}}
                             <div class="testcase testcase-skip result-item result-skipped">